@@ -0,0 +1,169 @@
+package amqpx
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// An ackBatcher coalesces the acknowledgements of the channel.
+//
+// The delivery tags are sequential on the channel, so the acks of the tags up to the lowest
+// unsettled tag are sent by one ack with the multiple flag. The acks above it are completed
+// out of order by the concurrent handlers, they are sent one by one when the batcher is flushed.
+// The nacks and rejects are sent immediately.
+type ackBatcher struct {
+	mx       sync.Mutex
+	ch       Acknowledger
+	size     int
+	interval time.Duration
+	stop     context.Context
+	log      *slog.Logger
+	timer    *time.Timer
+
+	// settled is the tag up to which all deliveries are settled
+	settled uint64
+	// done keeps the settled tags above settled, true if the ack is not sent
+	done map[uint64]bool
+	// last is the highest not sent ack up to settled
+	last    uint64
+	below   int
+	pending int
+}
+
+func newAckBatcher(size int, interval time.Duration, stop context.Context, log *slog.Logger) *ackBatcher {
+	return &ackBatcher{
+		size:     size,
+		interval: interval,
+		stop:     stop,
+		log:      log,
+		done:     make(map[uint64]bool),
+	}
+}
+
+// wrap replaces the acknowledger of the delivery by the batcher.
+func (b *ackBatcher) wrap(d *amqp091.Delivery) {
+	if b == nil {
+		return
+	}
+
+	b.mx.Lock()
+	if b.ch == nil {
+		b.ch = d.Acknowledger
+	}
+	b.mx.Unlock()
+	d.Acknowledger = b
+}
+
+func (b *ackBatcher) Ack(tag uint64, multiple bool) error {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if multiple {
+		return b.ch.Ack(tag, multiple)
+	}
+
+	b.settle(tag, true)
+
+	// the acks are sent promptly when the consumer is stopping
+	if b.pending >= b.size || b.stop.Err() != nil {
+		return b.flushLocked()
+	}
+
+	if b.timer == nil {
+		b.timer = time.AfterFunc(b.interval, b.flush)
+	}
+	return nil
+}
+
+func (b *ackBatcher) Nack(tag uint64, multiple bool, requeue bool) error {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if err := b.ch.Nack(tag, multiple, requeue); err != nil {
+		return err
+	}
+
+	b.settle(tag, false)
+	return nil
+}
+
+func (b *ackBatcher) Reject(tag uint64, requeue bool) error {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if err := b.ch.Reject(tag, requeue); err != nil {
+		return err
+	}
+
+	b.settle(tag, false)
+	return nil
+}
+
+// settle marks the tag as settled and moves the lowest unsettled tag.
+func (b *ackBatcher) settle(tag uint64, ack bool) {
+	if ack {
+		b.pending++
+	}
+	b.done[tag] = ack
+
+	for {
+		ack, ok := b.done[b.settled+1]
+		if !ok {
+			return
+		}
+
+		delete(b.done, b.settled+1)
+		b.settled++
+		if ack {
+			b.last = b.settled
+			b.below++
+		}
+	}
+}
+
+// flush sends the pending acks.
+func (b *ackBatcher) flush() {
+	if b == nil {
+		return
+	}
+
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if err := b.flushLocked(); err != nil {
+		b.log.Error("flush acks", LogKeyError, err)
+	}
+}
+
+func (b *ackBatcher) flushLocked() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	if b.below != 0 {
+		if err := b.ch.Ack(b.last, b.below > 1); err != nil {
+			return err
+		}
+
+		b.pending -= b.below
+		b.below, b.last = 0, 0
+	}
+
+	for tag, ack := range b.done {
+		if !ack {
+			continue
+		}
+
+		if err := b.ch.Ack(tag, false); err != nil {
+			return err
+		}
+		b.done[tag] = false
+		b.pending--
+	}
+	return nil
+}
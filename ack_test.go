@@ -0,0 +1,202 @@
+package amqpx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ackCalls struct {
+	mx    sync.Mutex
+	calls []string
+}
+
+func (a *ackCalls) add(format string, v ...any) error {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	a.calls = append(a.calls, fmt.Sprintf(format, v...))
+	return nil
+}
+
+func (a *ackCalls) take() []string {
+	a.mx.Lock()
+	defer a.mx.Unlock()
+
+	calls := a.calls
+	a.calls = nil
+	return calls
+}
+
+func (a *ackCalls) acknowledger() *AcknowledgerMock {
+	return &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error {
+			return a.add("ack %d %t", tag, multiple)
+		},
+		NackFunc: func(tag uint64, multiple bool, requeue bool) error {
+			return a.add("nack %d %t", tag, requeue)
+		},
+		RejectFunc: func(tag uint64, requeue bool) error {
+			return a.add("reject %d", tag)
+		},
+	}
+}
+
+func newTestAckBatcher(t *testing.T, calls *ackCalls, size int, stop context.Context) *ackBatcher {
+	b := newAckBatcher(size, time.Hour, stop, discardLogger)
+	d := &amqp091.Delivery{Acknowledger: calls.acknowledger()}
+	b.wrap(d)
+	require.Equal(t, b, d.Acknowledger)
+	return b
+}
+
+func TestAckBatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("contiguous", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &ackCalls{}
+		b := newTestAckBatcher(t, calls, 3, context.Background())
+
+		require.NoError(t, b.Ack(1, false))
+		require.NoError(t, b.Ack(2, false))
+		assert.Empty(t, calls.take())
+
+		require.NoError(t, b.Ack(3, false))
+		assert.Equal(t, []string{"ack 3 true"}, calls.take())
+	})
+
+	t.Run("out of order", func(t *testing.T) {
+		t.Parallel()
+
+		calls := &ackCalls{}
+		b := newTestAckBatcher(t, calls, 10, context.Background())
+
+		// the tag 2 is in-flight, so the tags 3 and 5 are not acknowledged by the multiple flag
+		require.NoError(t, b.Ack(1, false))
+		require.NoError(t, b.Ack(3, false))
+		require.NoError(t, b.Reject(4, false))
+		require.NoError(t, b.Ack(5, false))
+		b.flush()
+
+		got := calls.take()
+		sort.Strings(got[2:])
+		assert.Equal(t, []string{"reject 4", "ack 1 false", "ack 3 false", "ack 5 false"}, got)
+
+		// the already sent acks are not repeated
+		require.NoError(t, b.Ack(2, false))
+		require.NoError(t, b.Nack(6, false, true))
+		require.NoError(t, b.Ack(7, false))
+		require.NoError(t, b.Ack(8, false))
+		b.flush()
+		assert.Equal(t, []string{"nack 6 true", "ack 8 true"}, calls.take())
+		assert.Equal(t, uint64(8), b.settled)
+		assert.Empty(t, b.done)
+		assert.Equal(t, 0, b.pending)
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		t.Parallel()
+		defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+		calls := &ackCalls{}
+		b := newTestAckBatcher(t, calls, 10, context.Background())
+		b.interval = time.Millisecond
+
+		require.NoError(t, b.Ack(1, false))
+		require.NoError(t, b.Ack(2, false))
+		var got []string
+		for len(got) == 0 {
+			time.Sleep(time.Millisecond)
+			got = calls.take()
+		}
+		assert.Equal(t, []string{"ack 2 true"}, got)
+	})
+
+	t.Run("stopping", func(t *testing.T) {
+		t.Parallel()
+
+		stop, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		calls := &ackCalls{}
+		b := newTestAckBatcher(t, calls, 10, stop)
+
+		require.NoError(t, b.Ack(1, false))
+		assert.Equal(t, []string{"ack 1 false"}, calls.take())
+	})
+}
+
+func TestConsumer_AckCoalescing(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	calls := &ackCalls{}
+	ack := calls.acknowledger()
+	delivery := make(chan amqp091.Delivery, 3)
+	for tag := uint64(1); tag <= 3; tag++ {
+		delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: tag}
+	}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		return Ack
+	}), SetPrefetchCount(3), SetAckCoalescing(10, time.Hour))
+	require.NoError(t, err)
+	for cons.Stats().Acked != 3 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Empty(t, calls.take())
+
+	// the pending acks are sent when the consumer is cancelled
+	require.NoError(t, cons.Cancel(context.Background()))
+	assert.Equal(t, []string{"ack 3 true"}, calls.take())
+}
+
+func TestConsumer_AckCoalescingShutdown(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	calls := &ackCalls{}
+	delivery := make(chan amqp091.Delivery, 1)
+	delivery <- amqp091.Delivery{Acknowledger: calls.acknowledger(), DeliveryTag: 1}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		return Ack
+	}), SetAckCoalescing(100, time.Hour))
+	require.NoError(t, err)
+	for cons.Stats().Acked != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Empty(t, calls.take())
+
+	// the pending acks are sent before the connection is closed
+	require.NoError(t, client.Shutdown(context.Background()))
+	assert.Equal(t, []string{"ack 1 false"}, calls.take())
+}
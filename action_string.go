@@ -11,11 +11,12 @@ func _() {
 	_ = x[Ack-0]
 	_ = x[Nack-1]
 	_ = x[Reject-2]
+	_ = x[Retry-3]
 }
 
-const _Action_name = "AckNackReject"
+const _Action_name = "AckNackRejectRetry"
 
-var _Action_index = [...]uint8{0, 3, 7, 13}
+var _Action_index = [...]uint8{0, 3, 7, 13, 18}
 
 func (i Action) String() string {
 	if i < 0 || i >= Action(len(_Action_index)-1) {
@@ -0,0 +1,123 @@
+package amqpx
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// A QueueInfo represents the state of the queue.
+type QueueInfo struct {
+	Name      string
+	Messages  int
+	Consumers int
+}
+
+// QueueInspect returns the number of messages and consumers of the queue.
+// ErrNotFound is returned when the queue does not exist.
+//
+// The operations are made on a short-lived channel, so the failed operation does not close
+// the channels of the consumers and publishers.
+func (c *Client) QueueInspect(name string) (QueueInfo, error) {
+	var info QueueInfo
+	err := c.withChannel(func(channel Channel) error {
+		q, err := channel.QueueDeclarePassive(name, false, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+
+		info = QueueInfo{Name: q.Name, Messages: q.Messages, Consumers: q.Consumers}
+		return nil
+	})
+	if err != nil {
+		return QueueInfo{}, newAdminError("inspect queue", name, err)
+	}
+	return info, nil
+}
+
+// QueueExists returns true if the queue exists.
+func (c *Client) QueueExists(name string) (bool, error) {
+	_, err := c.QueueInspect(name)
+	return exists(err)
+}
+
+// QueuePurge removes all messages from the queue which are not waiting for acknowledgment.
+// It returns the number of purged messages.
+func (c *Client) QueuePurge(name string) (int, error) {
+	var n int
+	err := c.withChannel(func(channel Channel) (err error) {
+		n, err = channel.QueuePurge(name, false)
+		return err
+	})
+	if err != nil {
+		return 0, newAdminError("purge queue", name, err)
+	}
+	return n, nil
+}
+
+// QueueDelete deletes the queue and returns the number of purged messages.
+// ErrPreconditionFailed is returned when ifUnused is true and the queue has consumers
+// or ifEmpty is true and the queue has messages.
+func (c *Client) QueueDelete(name string, ifUnused, ifEmpty bool) (int, error) {
+	var n int
+	err := c.withChannel(func(channel Channel) (err error) {
+		n, err = channel.QueueDelete(name, ifUnused, ifEmpty, false)
+		return err
+	})
+	if err != nil {
+		return 0, newAdminError("delete queue", name, err)
+	}
+	return n, nil
+}
+
+// ExchangeExists returns true if the exchange exists.
+func (c *Client) ExchangeExists(name string) (bool, error) {
+	err := c.withChannel(func(channel Channel) error {
+		return channel.ExchangeDeclarePassive(name, "", false, false, false, false, nil)
+	})
+	if err != nil {
+		err = newAdminError("inspect exchange", name, err)
+	}
+	return exists(err)
+}
+
+// ExchangeDelete deletes the exchange.
+// ErrPreconditionFailed is returned when ifUnused is true and the exchange has bindings.
+func (c *Client) ExchangeDelete(name string, ifUnused bool) error {
+	err := c.withChannel(func(channel Channel) error {
+		return channel.ExchangeDelete(name, ifUnused, false)
+	})
+	if err != nil {
+		return newAdminError("delete exchange", name, err)
+	}
+	return nil
+}
+
+func exists(err error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+
+	default:
+		return false, err
+	}
+}
+
+// newAdminError classifies the server error which closes the channel.
+func newAdminError(op, name string, err error) error {
+	var amqpErr *amqp091.Error
+	if errors.As(err, &amqpErr) {
+		switch amqpErr.Code {
+		case amqp091.NotFound:
+			return fmt.Errorf("amqpx: %s %q: %w: %w", op, name, ErrNotFound, err)
+
+		case amqp091.PreconditionFailed:
+			return fmt.Errorf("amqpx: %s %q: %w: %w", op, name, ErrPreconditionFailed, err)
+		}
+	}
+	return fmt.Errorf("amqpx: %s %q: %w", op, name, err)
+}
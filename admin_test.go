@@ -0,0 +1,138 @@
+package amqpx
+
+import (
+	"testing"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func prepAdmin(t *testing.T) (*Client, *ChannelMock) {
+	client, mock := prep(t)
+	t.Cleanup(client.Close)
+
+	channel := &ChannelMock{
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+	mock.Conn.ChannelFunc = func() (Channel, error) {
+		return channel, nil
+	}
+	return client, channel
+}
+
+func TestClient_QueueInspect(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		client, channel := prepAdmin(t)
+		channel.QueueDeclarePassiveFunc = func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+			return amqp091.Queue{Name: name, Messages: 2, Consumers: 1}, nil
+		}
+
+		got, err := client.QueueInspect("foo")
+		require.NoError(t, err)
+		assert.Equal(t, QueueInfo{Name: "foo", Messages: 2, Consumers: 1}, got)
+		assert.Len(t, channel.CloseCalls(), 1)
+
+		ok, err := client.QueueExists("foo")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		client, channel := prepAdmin(t)
+		channel.QueueDeclarePassiveFunc = func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+			return amqp091.Queue{}, &amqp091.Error{Code: amqp091.NotFound, Reason: "NOT_FOUND - no queue 'foo'"}
+		}
+
+		_, err := client.QueueInspect("foo")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		var amqpErr *amqp091.Error
+		assert.ErrorAs(t, err, &amqpErr)
+
+		ok, err := client.QueueExists("foo")
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestClient_QueuePurge(t *testing.T) {
+	t.Parallel()
+
+	client, channel := prepAdmin(t)
+	channel.QueuePurgeFunc = func(name string, noWait bool) (int, error) {
+		return 3, nil
+	}
+
+	got, err := client.QueuePurge("foo")
+	require.NoError(t, err)
+	assert.Equal(t, 3, got)
+}
+
+func TestClient_QueueDelete(t *testing.T) {
+	t.Parallel()
+
+	client, channel := prepAdmin(t)
+	channel.QueueDeleteFunc = func(name string, ifUnused bool, ifEmpty bool, noWait bool) (int, error) {
+		if ifEmpty {
+			return 0, &amqp091.Error{Code: amqp091.PreconditionFailed, Reason: "PRECONDITION_FAILED - queue 'foo' not empty"}
+		}
+		return 1, nil
+	}
+
+	_, err := client.QueueDelete("foo", false, true)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	got, err := client.QueueDelete("foo", true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got)
+	assert.True(t, channel.QueueDeleteCalls()[1].IfUnused)
+}
+
+func TestClient_Exchange(t *testing.T) {
+	t.Parallel()
+
+	client, channel := prepAdmin(t)
+	channel.ExchangeDeclarePassiveFunc = func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+		if name == "foo" {
+			return nil
+		}
+		return &amqp091.Error{Code: amqp091.NotFound}
+	}
+	channel.ExchangeDeleteFunc = func(name string, ifUnused bool, noWait bool) error {
+		return &amqp091.Error{Code: amqp091.PreconditionFailed}
+	}
+
+	ok, err := client.ExchangeExists("foo")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.ExchangeExists("bar")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.ErrorIs(t, client.ExchangeDelete("foo", true), ErrPreconditionFailed)
+}
+
+func TestClient_AdminConnClosed(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+
+	mock.Conn.IsClosedFunc = func() bool {
+		return true
+	}
+
+	_, err := client.QueueInspect("foo")
+	assert.ErrorIs(t, err, errConnClosed)
+}
@@ -7,15 +7,50 @@ import (
 	"github.com/rabbitmq/amqp091-go"
 )
 
+var (
+	// ErrClosed is returned by Client.Err when the client is closed.
+	ErrClosed = fmt.Errorf("amqpx: client closed")
+
+	// ErrConnectionBlocked is returned by Publisher.Publish when the connection is blocked by the server.
+	ErrConnectionBlocked = fmt.Errorf("connection blocked")
+
+	// ErrNotFound is returned when the queue or the exchange does not exist (404 NOT_FOUND).
+	ErrNotFound = fmt.Errorf("not found")
+
+	// ErrPreconditionFailed is returned when the queue or the exchange does not satisfy the condition
+	// (406 PRECONDITION_FAILED), ex: the queue is in use or not empty.
+	ErrPreconditionFailed = fmt.Errorf("precondition failed")
+)
+
 var (
 	errChannelClosed       = fmt.Errorf("channel/connection is not open")
 	errPublishConfirm      = fmt.Errorf("publish has not confirmation")
 	errUnmarshalerNotFound = fmt.Errorf("unmarshaler not found")
 	errMarshalerNotFound   = fmt.Errorf("marshaler not found")
 	errRoutingKeyEmpty     = fmt.Errorf("routing-key is empty")
+	errRouteFuncType       = fmt.Errorf("routing func does not match message type")
+
+	errConnClosed  = fmt.Errorf("connection closed")
+	errMaxAttempts = fmt.Errorf("max reconnect attempts exceeded")
+	errFuncNil     = fmt.Errorf("consumer func nil")
+
+	errStreamAutoAck  = fmt.Errorf("stream consumer does not support auto-ack mode")
+	errStreamPrefetch = fmt.Errorf("stream consumer requires prefetch count")
+
+	errBatchLimits   = fmt.Errorf("batch size and wait must be positive")
+	errBatchPrefetch = fmt.Errorf("prefetch count is less than batch size")
+
+	errAckCoalescingLimits  = fmt.Errorf("ack coalescing size and interval must be positive")
+	errAckCoalescingAutoAck = fmt.Errorf("ack coalescing does not support auto-ack mode")
+	errAckCoalescingBatch   = fmt.Errorf("ack coalescing does not support batch handler")
+
+	errRetryPolicy       = fmt.Errorf("retry policy requires positive delays")
+	errRetryAutoAck      = fmt.Errorf("retry policy does not support auto-ack mode")
+	errRetryQueue        = fmt.Errorf("retry policy requires queue name")
+	errRetryPolicyNotSet = fmt.Errorf("retry policy is not set")
 
-	errConnClosed = fmt.Errorf("connection closed")
-	errFuncNil    = fmt.Errorf("consumer func nil")
+	errPoisonPolicy  = fmt.Errorf("poison policy requires non-negative max redeliveries")
+	errPoisonAutoAck = fmt.Errorf("poison policy does not support auto-ack mode")
 )
 
 // The delivery mode of messages is unrelated to the durability of the queues they reside on.
@@ -36,14 +36,14 @@ func (i *Interceptor) WrapConsume(next amqpx.ConsumeFunc) amqpx.ConsumeFunc {
 
 		r, err := gzip.NewReader(bytes.NewReader(req.Body()))
 		if err != nil {
-			req.Log("[ERROR] amqpxgzip: init reader: %s", err)
+			req.Logger().Error("amqpxgzip: init reader", amqpx.LogKeyError, err)
 			return amqpx.Reject
 		}
 		defer r.Close()
 
 		body, err := io.ReadAll(r)
 		if err != nil {
-			req.Log("[ERROR] amqpxgzip: read: %s", err)
+			req.Logger().Error("amqpxgzip: read", amqpx.LogKeyError, err)
 			return amqpx.Reject
 		}
 
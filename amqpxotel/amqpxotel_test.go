@@ -2,12 +2,14 @@ package amqpxotel
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/rabbitmq/amqp091-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
@@ -40,6 +42,27 @@ func TestInterceptor_WrapConsume(t *testing.T) {
 	}}, spanRecorder.Ended())
 }
 
+func TestInterceptor_WrapConsumeErr(t *testing.T) {
+	t.Parallel()
+
+	spanRecorder := tracetest.NewSpanRecorder()
+	traceProvider := trace.NewTracerProvider(trace.WithSpanProcessor(spanRecorder))
+
+	fn := NewInterceptor(WithTracerProvider(traceProvider)).WrapConsume(func(ctx context.Context, req *amqpx.DeliveryRequest) amqpx.Action {
+		req.SetErr(errors.New("failed"))
+		return amqpx.Reject
+	})
+
+	d := (&amqpx.DeliveryRequest{}).NewFrom(&amqp091.Delivery{Exchange: "direct", RoutingKey: "key"})
+	require.Equal(t, amqpx.Reject, fn(context.Background(), d))
+
+	spans := spanRecorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, trace.Status{Code: codes.Error, Description: "failed"}, spans[0].Status())
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
 func TestInterceptor_WrapPublish(t *testing.T) {
 	t.Parallel()
 
@@ -1,9 +1,9 @@
 module github.com/itcomusic/amqpx/amqpxotel
 
-go 1.20
+go 1.21
 
 require (
-	github.com/itcomusic/amqpx v0.3.1
+	github.com/itcomusic/amqpx v0.3.2-0.20261018014838-13cd62872f4e
 	github.com/rabbitmq/amqp091-go v1.9.0
 	github.com/stretchr/testify v1.8.4
 	go.opentelemetry.io/otel v1.19.0
@@ -5,6 +5,7 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
 	"go.opentelemetry.io/otel/trace"
 
@@ -114,6 +115,10 @@ func (i *Interceptor) WrapConsume(next amqpx.ConsumeFunc) amqpx.ConsumeFunc {
 
 		status := next(ctx, req)
 		code, desc := i.config.ackStatus(status)
+		if err := req.Err(); err != nil {
+			span.RecordError(err)
+			code, desc = codes.Error, err.Error()
+		}
 		span.SetStatus(code, desc)
 		return status
 	}
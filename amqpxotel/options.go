@@ -95,6 +95,7 @@ func WithPublishSpanName(operationName func(context.Context, *amqpx.PublishingRe
 
 // WithAckStatus configures the Interceptor to handle the acknowledgment status.
 // By default, amqpx.Ack sets codes.Ok and other codes.Unset.
+// The error of the delivery (amqpx.DeliveryRequest.Err) is recorded and sets codes.Error.
 func WithAckStatus(ackStatus func(amqpx.Action) (code codes.Code, description string)) Option {
 	return func(c *config) {
 		if ackStatus != nil {
@@ -0,0 +1,82 @@
+package amqpx
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultBackoffInitial    = time.Second
+	defaultBackoffMax        = time.Minute
+	defaultBackoffMultiplier = 2
+)
+
+var defaultBackoff Backoff = ConstantBackoff{Delay: defaultReconnectDelay}
+
+// A Backoff is an interface implemented by the reconnect policy.
+type Backoff interface {
+	// Next returns delay before the next attempt.
+	// The attempt starts with 1 and err is the last error.
+	// The false result means no more attempts.
+	Next(attempt int, err error) (time.Duration, bool)
+}
+
+// BackoffFunc type is an adapter to allow the use of ordinary functions as Backoff.
+type BackoffFunc func(attempt int, err error) (time.Duration, bool)
+
+// Next calls f(attempt, err).
+func (f BackoffFunc) Next(attempt int, err error) (time.Duration, bool) {
+	return f(attempt, err)
+}
+
+// A ConstantBackoff represents the same delay between attempts.
+type ConstantBackoff struct {
+	Delay time.Duration
+	// MaxAttempts limits the number of attempts, zero means no limit.
+	MaxAttempts int
+}
+
+// Next implements Backoff.
+func (b ConstantBackoff) Next(attempt int, _ error) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempt >= b.MaxAttempts {
+		return 0, false
+	}
+	return b.Delay, true
+}
+
+// An ExponentialBackoff represents the exponential growing delay between attempts.
+// Zero values are replaced by defaults: Initial is 1s, Max is 1m, Multiplier is 2.
+type ExponentialBackoff struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+	// Jitter randomly decreases the delay by fraction [0, 1] to spread out attempts of many clients.
+	Jitter float64
+	// MaxAttempts limits the number of attempts, zero means no limit.
+	MaxAttempts int
+}
+
+// Next implements Backoff.
+func (b ExponentialBackoff) Next(attempt int, _ error) (time.Duration, bool) {
+	if b.MaxAttempts > 0 && attempt >= b.MaxAttempts {
+		return 0, false
+	}
+
+	initial, max, multiplier := b.Initial, b.Max, b.Multiplier
+	if initial <= 0 {
+		initial = defaultBackoffInitial
+	}
+	if max <= 0 {
+		max = defaultBackoffMax
+	}
+	if multiplier < 1 {
+		multiplier = defaultBackoffMultiplier
+	}
+
+	delay := time.Duration(math.Min(float64(initial)*math.Pow(multiplier, float64(attempt-1)), float64(max)))
+	if jitter := math.Min(b.Jitter, 1); jitter > 0 {
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+	return delay, true
+}
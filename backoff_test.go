@@ -0,0 +1,53 @@
+package amqpx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantBackoff(t *testing.T) {
+	t.Parallel()
+
+	b := ConstantBackoff{Delay: time.Second, MaxAttempts: 2}
+	got, ok := b.Next(1, nil)
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, got)
+
+	_, ok = b.Next(2, nil)
+	assert.False(t, ok)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("capped", func(t *testing.T) {
+		t.Parallel()
+
+		b := ExponentialBackoff{Initial: time.Second, Max: 5 * time.Second, Multiplier: 2}
+		for i, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+			got, ok := b.Next(i+1, nil)
+			assert.True(t, ok)
+			assert.Equal(t, want, got)
+		}
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		t.Parallel()
+
+		b := ExponentialBackoff{Initial: time.Second, Jitter: 0.5}
+		for i := 0; i < 100; i++ {
+			got, ok := b.Next(1, nil)
+			assert.True(t, ok)
+			assert.True(t, got > time.Second/2 && got <= time.Second, got)
+		}
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := ExponentialBackoff{MaxAttempts: 3}.Next(3, nil)
+		assert.False(t, ok)
+	})
+}
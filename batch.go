@@ -0,0 +1,235 @@
+package amqpx
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+type batchHandler interface {
+	HandlerValue
+	limits() (size int, wait time.Duration)
+	serveBatch(ctx context.Context, reqs []*DeliveryRequest) []Action
+}
+
+// batchValue represents consume batch of messages, it unmarshales bytes into
+// the appropriate struct based on the signature of the func.
+type batchValue[T any] struct {
+	fn          func(context.Context, []*Delivery[T]) []Action
+	size        int
+	wait        time.Duration
+	unmarshaler map[string]Unmarshaler
+	bytesMsg    bool
+}
+
+// B represents handler of consume batch of amqpx.Delivery[T].
+// The batch is handled when size deliveries are collected or wait is passed since the first delivery.
+//
+// The handler returns one action which is applied to the whole batch or an action per delivery.
+// The contiguous deliveries with the same action are acknowledged by one call,
+// so the batches are handled one at a time.
+// The consume interceptors are applied per delivery before decoding,
+// each chain waits until the batch is handled and returns the action of its delivery.
+func B[T any](size int, wait time.Duration, fn func(ctx context.Context, batch []*Delivery[T]) []Action) *batchValue[T] {
+	_, bytesMsg := any(new(T)).(*[]byte)
+	return &batchValue[T]{fn: fn, size: size, wait: wait, bytesMsg: bytesMsg}
+}
+
+func (v *batchValue[T]) init(m map[string]Unmarshaler, _ ErrorPolicy) {
+	v.unmarshaler = m
+}
+
+func (v *batchValue[T]) limits() (int, time.Duration) {
+	return v.size, v.wait
+}
+
+func (v *batchValue[T]) serve(ctx context.Context, req *DeliveryRequest) Action {
+	return v.serveBatch(ctx, []*DeliveryRequest{req})[0]
+}
+
+func (v *batchValue[T]) serveBatch(ctx context.Context, reqs []*DeliveryRequest) []Action {
+	actions := make([]Action, len(reqs))
+	batch := make([]*Delivery[T], 0, len(reqs))
+	index := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		d, ok := decodeDelivery[T](req, v.unmarshaler, v.bytesMsg)
+		if !ok {
+			actions[i] = Reject
+			continue
+		}
+
+		batch = append(batch, d)
+		index = append(index, i)
+	}
+
+	if len(batch) == 0 {
+		return actions
+	}
+
+	result := v.fn(ctx, batch)
+	switch len(result) {
+	case 1:
+		for _, i := range index {
+			actions[i] = result[0]
+		}
+
+	case len(batch):
+		for j, i := range index {
+			actions[i] = result[j]
+		}
+
+	default:
+		err := fmt.Errorf("batch handler returned %d actions for %d deliveries", len(result), len(batch))
+		for _, i := range index {
+			reqs[i].Logger().Error("requeue delivery", LogKeyError, err)
+			actions[i] = Nack
+		}
+	}
+	return actions
+}
+
+// A batch represents the deliveries collected by the serve goroutine.
+type batch struct {
+	fn      func(ctx context.Context, reqs []*DeliveryRequest) []Action
+	size    int
+	wait    time.Duration
+	pending []*amqp091.Delivery
+	timer   *time.Timer
+}
+
+func newBatch(h batchHandler) *batch {
+	size, wait := h.limits()
+	return &batch{fn: h.serveBatch, size: size, wait: wait}
+}
+
+// add adds the delivery and returns true if the batch is full.
+func (b *batch) add(d *amqp091.Delivery) bool {
+	if len(b.pending) == 0 {
+		b.timer = time.NewTimer(b.wait)
+	}
+
+	b.pending = append(b.pending, d)
+	return len(b.pending) >= b.size
+}
+
+// expired returns the channel which is fired when the wait of the pending deliveries is passed.
+func (b *batch) expired() <-chan time.Time {
+	if b == nil || b.timer == nil {
+		return nil
+	}
+	return b.timer.C
+}
+
+// take returns the pending deliveries and resets the batch.
+func (b *batch) take() []*amqp091.Delivery {
+	if b == nil {
+		return nil
+	}
+
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	pending := b.pending
+	b.pending = nil
+	return pending
+}
+
+// interceptBatch runs the chain made by wrap per delivery, the deliveries which pass
+// through the chains are handled by fn as one batch. The chain which does not call the next
+// returns the action of its delivery without handling.
+func interceptBatch(fn func(context.Context, []*DeliveryRequest) []Action, wrap func(ConsumeFunc) ConsumeFunc) func(context.Context, []*DeliveryRequest) []Action {
+	type entry struct {
+		index  int
+		req    *DeliveryRequest
+		action chan Action
+	}
+
+	return func(ctx context.Context, reqs []*DeliveryRequest) []Action {
+		actions := make([]Action, len(reqs))
+		entered := make(chan entry, len(reqs))
+		returned := make(chan struct{}, len(reqs))
+
+		var wg sync.WaitGroup
+		for i, req := range reqs {
+			wg.Add(1)
+			go func(i int, req *DeliveryRequest) {
+				defer wg.Done()
+
+				var in atomic.Bool
+				actions[i] = wrap(func(_ context.Context, req *DeliveryRequest) Action {
+					in.Store(true)
+					e := entry{index: i, req: req, action: make(chan Action, 1)}
+					entered <- e
+					return <-e.action
+				})(ctx, req)
+
+				if !in.Load() {
+					returned <- struct{}{}
+				}
+			}(i, req)
+		}
+
+		// each chain either enters the end or returns
+		batch := make([]entry, 0, len(reqs))
+		for range reqs {
+			select {
+			case e := <-entered:
+				batch = append(batch, e)
+			case <-returned:
+			}
+		}
+
+		if len(batch) != 0 {
+			// the deliveries are kept in order to be acknowledged together
+			sort.Slice(batch, func(i, j int) bool { return batch[i].index < batch[j].index })
+			handled := make([]*DeliveryRequest, len(batch))
+			for i, e := range batch {
+				handled[i] = e.req
+			}
+
+			for i, action := range fn(ctx, handled) {
+				batch[i].action <- action
+			}
+		}
+
+		wg.Wait()
+		return actions
+	}
+}
+
+// settleBatch acknowledges the deliveries in order. The contiguous deliveries with the same action
+// are acknowledged by one call with the multiple flag, it is safe as the batches are handled one at a time.
+func settleBatch(reqs []*DeliveryRequest, actions []Action) []error {
+	errs := make([]error, len(reqs))
+	for i := 0; i < len(reqs); {
+		j := i + 1
+		for j < len(reqs) && actions[j] == actions[i] &&
+			reqs[j].in.Acknowledger == reqs[j-1].in.Acknowledger &&
+			reqs[j].in.DeliveryTag == reqs[j-1].in.DeliveryTag+1 {
+			j++
+		}
+
+		var err error
+		if j-i == 1 {
+			err = reqs[i].setStatus(actions[i])
+		} else {
+			err = reqs[j-1].setStatusMultiple(actions[i])
+		}
+
+		for k := i; k < j; k++ {
+			errs[k] = err
+			if err == nil {
+				reqs[k].status = actions[i]
+			}
+		}
+		i = j
+	}
+	return errs
+}
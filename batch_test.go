@@ -0,0 +1,261 @@
+package amqpx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchValue_serveBatch(t *testing.T) {
+	t.Parallel()
+
+	type Gopher struct {
+		Name string `json:"name"`
+	}
+
+	reqs := func() []*DeliveryRequest {
+		return []*DeliveryRequest{
+			{in: &amqp091.Delivery{Body: []byte(`{"name":"a"}`), ContentType: testUnmarshaler.ContentType()}},
+			{in: &amqp091.Delivery{Body: []byte(`{"name":"b"}`), ContentType: "text/plain"}},
+			{in: &amqp091.Delivery{Body: []byte(`{"name":"c"}`), ContentType: testUnmarshaler.ContentType()}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		result []Action
+		want   []Action
+	}{
+		{name: "per batch", result: []Action{Ack}, want: []Action{Ack, Reject, Ack}},
+		{name: "per message", result: []Action{Nack, Ack}, want: []Action{Nack, Reject, Ack}},
+		{name: "wrong number", result: []Action{Ack, Ack, Ack}, want: []Action{Nack, Reject, Nack}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fn := B(10, time.Second, func(ctx context.Context, batch []*Delivery[Gopher]) []Action {
+				// the delivery which can not be unmarshaled is rejected
+				assert.Equal(t, []*Gopher{{Name: "a"}, {Name: "c"}}, []*Gopher{batch[0].Msg, batch[1].Msg})
+				return tt.result
+			})
+			fn.init(map[string]Unmarshaler{testUnmarshaler.ContentType(): testUnmarshaler}, DefaultErrorPolicy)
+			assert.Equal(t, tt.want, fn.serveBatch(context.Background(), reqs()))
+		})
+	}
+}
+
+func TestSettleBatch(t *testing.T) {
+	t.Parallel()
+
+	var mx sync.Mutex
+	var calls []string
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error {
+			mx.Lock()
+			defer mx.Unlock()
+			calls = append(calls, fmt.Sprintf("ack %d %t", tag, multiple))
+			return nil
+		},
+		NackFunc: func(tag uint64, multiple bool, requeue bool) error {
+			mx.Lock()
+			defer mx.Unlock()
+			calls = append(calls, fmt.Sprintf("nack %d %t %t", tag, multiple, requeue))
+			return nil
+		},
+		RejectFunc: func(tag uint64, requeue bool) error {
+			mx.Lock()
+			defer mx.Unlock()
+			calls = append(calls, fmt.Sprintf("reject %d", tag))
+			return fmt.Errorf("failed")
+		},
+	}
+
+	var reqs []*DeliveryRequest
+	for _, tag := range []uint64{1, 2, 3, 4, 5, 7, 8, 9} {
+		reqs = append(reqs, &DeliveryRequest{in: &amqp091.Delivery{Acknowledger: ack, DeliveryTag: tag}})
+	}
+
+	errs := settleBatch(reqs, []Action{Ack, Ack, Ack, Reject, Nack, Nack, Reject, Reject})
+	assert.Equal(t, []string{
+		"ack 3 true",
+		"reject 4",
+		"nack 5 false true",
+		// the delivery tags are not contiguous
+		"nack 7 false true",
+		"nack 9 true false",
+	}, calls)
+
+	assert.Equal(t, []error{nil, nil, nil, fmt.Errorf("failed"), nil, nil, nil, nil}, errs)
+	for i, want := range []Action{Ack, Ack, Ack, Ack, Nack, Nack, Reject, Reject} {
+		assert.Equal(t, want, reqs[i].Status())
+	}
+}
+
+func TestInterceptBatch(t *testing.T) {
+	t.Parallel()
+
+	var observed []Action
+	var mx sync.Mutex
+	fn := interceptBatch(func(ctx context.Context, reqs []*DeliveryRequest) []Action {
+		require.Len(t, reqs, 2)
+		assert.Equal(t, []byte("a"), reqs[0].Body())
+		assert.Equal(t, []byte("c"), reqs[1].Body())
+		return []Action{Ack, Nack}
+	}, func(next ConsumeFunc) ConsumeFunc {
+		return func(ctx context.Context, req *DeliveryRequest) Action {
+			if string(req.Body()) == "B" {
+				return Reject
+			}
+
+			req.SetBody(bytes.ToLower(req.Body()))
+			action := next(ctx, req)
+			mx.Lock()
+			observed = append(observed, action)
+			mx.Unlock()
+			return action
+		}
+	})
+
+	reqs := []*DeliveryRequest{
+		{in: &amqp091.Delivery{Body: []byte("A")}},
+		{in: &amqp091.Delivery{Body: []byte("B")}},
+		{in: &amqp091.Delivery{Body: []byte("C")}},
+	}
+	assert.Equal(t, []Action{Ack, Reject, Nack}, fn(context.Background(), reqs))
+	assert.ElementsMatch(t, []Action{Ack, Nack}, observed)
+}
+
+func TestConsumer_Batch(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error { return nil },
+	}
+	delivery := make(chan amqp091.Delivery, 3)
+	for tag := uint64(1); tag <= 3; tag++ {
+		delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: tag, Body: []byte{byte(tag)}}
+	}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	batches := make(chan [][]byte, 2)
+	cons, err := client.NewConsumer("foo", B(2, time.Millisecond*10, func(ctx context.Context, batch []*Delivery[[]byte]) []Action {
+		var msg [][]byte
+		for _, d := range batch {
+			msg = append(msg, *d.Msg)
+		}
+		batches <- msg
+		return []Action{Ack}
+	}))
+	require.NoError(t, err)
+
+	// the full batch is handled at once and the rest after the wait
+	assert.Equal(t, [][]byte{{1}, {2}}, <-batches)
+	assert.Equal(t, [][]byte{{3}}, <-batches)
+	assert.Equal(t, 2, mock.Channel.QosCalls()[0].PrefetchCount)
+
+	require.NoError(t, cons.Cancel(context.Background()))
+	assert.Equal(t, uint64(3), cons.Stats().Acked)
+	assert.Equal(t, []struct {
+		Tag      uint64
+		Multiple bool
+	}{{Tag: 2, Multiple: true}, {Tag: 3, Multiple: false}}, ack.AckCalls())
+}
+
+func TestConsumer_BatchAutoAckReconnect(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	var calls atomic.Int32
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		delivery := make(chan amqp091.Delivery, 2)
+		if calls.Add(1) == 1 {
+			// the channel is lost with the pending batch
+			delivery <- amqp091.Delivery{DeliveryTag: 1, Body: []byte{1}}
+			delivery <- amqp091.Delivery{DeliveryTag: 2, Body: []byte{2}}
+			close(delivery)
+		}
+		return delivery, nil
+	}
+
+	handled := make(chan error, 1)
+	_, err := client.NewConsumer("foo", B(10, time.Hour, func(ctx context.Context, batch []*Delivery[[]byte]) []Action {
+		assert.Len(t, batch, 2)
+		handled <- ctx.Err()
+		return []Action{Ack}
+	}), SetAutoAckMode())
+	require.NoError(t, err)
+
+	// the deliveries are acknowledged by the server, so they are handled with the live context
+	assert.NoError(t, <-handled)
+}
+
+func TestConsumer_BatchInterceptor(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error { return nil },
+	}
+	delivery := make(chan amqp091.Delivery, 2)
+	for tag := uint64(1); tag <= 2; tag++ {
+		delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: tag, Body: []byte{byte(tag)}}
+	}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	var intercepted atomic.Int32
+	batches := make(chan [][]byte, 1)
+	cons, err := client.NewConsumer("foo", B(2, time.Hour, func(ctx context.Context, batch []*Delivery[[]byte]) []Action {
+		var msg [][]byte
+		for _, d := range batch {
+			msg = append(msg, *d.Msg)
+		}
+		batches <- msg
+		return []Action{Ack}
+	}), SetConsumeInterceptor(func(next ConsumeFunc) ConsumeFunc {
+		return func(ctx context.Context, req *DeliveryRequest) Action {
+			intercepted.Add(1)
+			req.SetBody(append(req.Body(), 0))
+			return next(ctx, req)
+		}
+	}))
+	require.NoError(t, err)
+
+	// the interceptors are applied per delivery before decoding
+	assert.Equal(t, [][]byte{{1, 0}, {2, 0}}, <-batches)
+	require.NoError(t, cons.Cancel(context.Background()))
+	assert.Equal(t, int32(2), intercepted.Load())
+	assert.Equal(t, uint64(2), cons.Stats().Acked)
+}
@@ -0,0 +1,98 @@
+package amqpx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// Blocking notifies the server's TCP flow control of the connection.
+type Blocking = amqp091.Blocking
+
+// A blocking represents flow control state of the connection.
+// The server blocks the connection when it hits memory or disk alarms.
+type blocking struct {
+	mx        sync.RWMutex
+	blocked   bool
+	unblocked chan struct{}
+	listeners []chan Blocking
+}
+
+func newBlocking() *blocking {
+	ch := make(chan struct{})
+	close(ch)
+	return &blocking{unblocked: ch}
+}
+
+func (b *blocking) set(v Blocking) {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	if b.blocked == v.Active {
+		return
+	}
+
+	b.blocked = v.Active
+	if v.Active {
+		b.unblocked = make(chan struct{})
+	} else {
+		close(b.unblocked)
+	}
+
+	for _, ch := range b.listeners {
+		select {
+		case ch <- v:
+		default:
+		}
+	}
+}
+
+func (b *blocking) isBlocked() bool {
+	b.mx.RLock()
+	defer b.mx.RUnlock()
+	return b.blocked
+}
+
+// wait waits until the connection is unblocked, ctx or done is done.
+// The cause of done is returned, it is ErrClosed when the client is closed.
+func (b *blocking) wait(ctx context.Context, done context.Context) error {
+	b.mx.RLock()
+	unblocked := b.unblocked
+	b.mx.RUnlock()
+
+	select {
+	case <-unblocked:
+		return nil
+	default:
+	}
+
+	select {
+	case <-unblocked:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+
+	case <-done.Done():
+		return context.Cause(done)
+	}
+}
+
+func (b *blocking) notify(ch chan Blocking) chan Blocking {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	b.listeners = append(b.listeners, ch)
+	return ch
+}
+
+func (b *blocking) close() {
+	b.mx.Lock()
+	defer b.mx.Unlock()
+
+	for _, ch := range b.listeners {
+		close(ch)
+	}
+	b.listeners = nil
+}
@@ -19,6 +19,9 @@ var _ Channel = &ChannelMock{}
 //
 //		// make and configure a mocked Channel
 //		mockedChannel := &ChannelMock{
+//			CancelFunc: func(consumer string, noWait bool) error {
+//				panic("mock out the Cancel method")
+//			},
 //			CloseFunc: func() error {
 //				panic("mock out the Close method")
 //			},
@@ -28,9 +31,21 @@ var _ Channel = &ChannelMock{}
 //			ConsumeFunc: func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
 //				panic("mock out the Consume method")
 //			},
+//			ExchangeBindFunc: func(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+//				panic("mock out the ExchangeBind method")
+//			},
 //			ExchangeDeclareFunc: func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
 //				panic("mock out the ExchangeDeclare method")
 //			},
+//			ExchangeDeclarePassiveFunc: func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+//				panic("mock out the ExchangeDeclarePassive method")
+//			},
+//			ExchangeDeleteFunc: func(name string, ifUnused bool, noWait bool) error {
+//				panic("mock out the ExchangeDelete method")
+//			},
+//			ExchangeUnbindFunc: func(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+//				panic("mock out the ExchangeUnbind method")
+//			},
 //			NotifyCancelFunc: func(stringCh chan string) chan string {
 //				panic("mock out the NotifyCancel method")
 //			},
@@ -52,6 +67,18 @@ var _ Channel = &ChannelMock{}
 //			QueueDeclareFunc: func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
 //				panic("mock out the QueueDeclare method")
 //			},
+//			QueueDeclarePassiveFunc: func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+//				panic("mock out the QueueDeclarePassive method")
+//			},
+//			QueueDeleteFunc: func(name string, ifUnused bool, ifEmpty bool, noWait bool) (int, error) {
+//				panic("mock out the QueueDelete method")
+//			},
+//			QueuePurgeFunc: func(name string, noWait bool) (int, error) {
+//				panic("mock out the QueuePurge method")
+//			},
+//			QueueUnbindFunc: func(name string, key string, exchange string, args amqp091.Table) error {
+//				panic("mock out the QueueUnbind method")
+//			},
 //		}
 //
 //		// use mockedChannel in code that requires Channel
@@ -59,6 +86,9 @@ var _ Channel = &ChannelMock{}
 //
 //	}
 type ChannelMock struct {
+	// CancelFunc mocks the Cancel method.
+	CancelFunc func(consumer string, noWait bool) error
+
 	// CloseFunc mocks the Close method.
 	CloseFunc func() error
 
@@ -68,9 +98,21 @@ type ChannelMock struct {
 	// ConsumeFunc mocks the Consume method.
 	ConsumeFunc func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error)
 
+	// ExchangeBindFunc mocks the ExchangeBind method.
+	ExchangeBindFunc func(destination string, key string, source string, noWait bool, args amqp091.Table) error
+
 	// ExchangeDeclareFunc mocks the ExchangeDeclare method.
 	ExchangeDeclareFunc func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error
 
+	// ExchangeDeclarePassiveFunc mocks the ExchangeDeclarePassive method.
+	ExchangeDeclarePassiveFunc func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error
+
+	// ExchangeDeleteFunc mocks the ExchangeDelete method.
+	ExchangeDeleteFunc func(name string, ifUnused bool, noWait bool) error
+
+	// ExchangeUnbindFunc mocks the ExchangeUnbind method.
+	ExchangeUnbindFunc func(destination string, key string, source string, noWait bool, args amqp091.Table) error
+
 	// NotifyCancelFunc mocks the NotifyCancel method.
 	NotifyCancelFunc func(stringCh chan string) chan string
 
@@ -92,8 +134,27 @@ type ChannelMock struct {
 	// QueueDeclareFunc mocks the QueueDeclare method.
 	QueueDeclareFunc func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error)
 
+	// QueueDeclarePassiveFunc mocks the QueueDeclarePassive method.
+	QueueDeclarePassiveFunc func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error)
+
+	// QueueDeleteFunc mocks the QueueDelete method.
+	QueueDeleteFunc func(name string, ifUnused bool, ifEmpty bool, noWait bool) (int, error)
+
+	// QueuePurgeFunc mocks the QueuePurge method.
+	QueuePurgeFunc func(name string, noWait bool) (int, error)
+
+	// QueueUnbindFunc mocks the QueueUnbind method.
+	QueueUnbindFunc func(name string, key string, exchange string, args amqp091.Table) error
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// Cancel holds details about calls to the Cancel method.
+		Cancel []struct {
+			// Consumer is the consumer argument value.
+			Consumer string
+			// NoWait is the noWait argument value.
+			NoWait bool
+		}
 		// Close holds details about calls to the Close method.
 		Close []struct {
 		}
@@ -119,6 +180,19 @@ type ChannelMock struct {
 			// Args is the args argument value.
 			Args amqp091.Table
 		}
+		// ExchangeBind holds details about calls to the ExchangeBind method.
+		ExchangeBind []struct {
+			// Destination is the destination argument value.
+			Destination string
+			// Key is the key argument value.
+			Key string
+			// Source is the source argument value.
+			Source string
+			// NoWait is the noWait argument value.
+			NoWait bool
+			// Args is the args argument value.
+			Args amqp091.Table
+		}
 		// ExchangeDeclare holds details about calls to the ExchangeDeclare method.
 		ExchangeDeclare []struct {
 			// Name is the name argument value.
@@ -136,6 +210,45 @@ type ChannelMock struct {
 			// Args is the args argument value.
 			Args amqp091.Table
 		}
+		// ExchangeDeclarePassive holds details about calls to the ExchangeDeclarePassive method.
+		ExchangeDeclarePassive []struct {
+			// Name is the name argument value.
+			Name string
+			// Kind is the kind argument value.
+			Kind string
+			// Durable is the durable argument value.
+			Durable bool
+			// AutoDelete is the autoDelete argument value.
+			AutoDelete bool
+			// Internal is the internal argument value.
+			Internal bool
+			// NoWait is the noWait argument value.
+			NoWait bool
+			// Args is the args argument value.
+			Args amqp091.Table
+		}
+		// ExchangeDelete holds details about calls to the ExchangeDelete method.
+		ExchangeDelete []struct {
+			// Name is the name argument value.
+			Name string
+			// IfUnused is the ifUnused argument value.
+			IfUnused bool
+			// NoWait is the noWait argument value.
+			NoWait bool
+		}
+		// ExchangeUnbind holds details about calls to the ExchangeUnbind method.
+		ExchangeUnbind []struct {
+			// Destination is the destination argument value.
+			Destination string
+			// Key is the key argument value.
+			Key string
+			// Source is the source argument value.
+			Source string
+			// NoWait is the noWait argument value.
+			NoWait bool
+			// Args is the args argument value.
+			Args amqp091.Table
+		}
 		// NotifyCancel holds details about calls to the NotifyCancel method.
 		NotifyCancel []struct {
 			// StringCh is the stringCh argument value.
@@ -203,11 +316,60 @@ type ChannelMock struct {
 			// Args is the args argument value.
 			Args amqp091.Table
 		}
+		// QueueDeclarePassive holds details about calls to the QueueDeclarePassive method.
+		QueueDeclarePassive []struct {
+			// Name is the name argument value.
+			Name string
+			// Durable is the durable argument value.
+			Durable bool
+			// AutoDelete is the autoDelete argument value.
+			AutoDelete bool
+			// Exclusive is the exclusive argument value.
+			Exclusive bool
+			// NoWait is the noWait argument value.
+			NoWait bool
+			// Args is the args argument value.
+			Args amqp091.Table
+		}
+		// QueueDelete holds details about calls to the QueueDelete method.
+		QueueDelete []struct {
+			// Name is the name argument value.
+			Name string
+			// IfUnused is the ifUnused argument value.
+			IfUnused bool
+			// IfEmpty is the ifEmpty argument value.
+			IfEmpty bool
+			// NoWait is the noWait argument value.
+			NoWait bool
+		}
+		// QueuePurge holds details about calls to the QueuePurge method.
+		QueuePurge []struct {
+			// Name is the name argument value.
+			Name string
+			// NoWait is the noWait argument value.
+			NoWait bool
+		}
+		// QueueUnbind holds details about calls to the QueueUnbind method.
+		QueueUnbind []struct {
+			// Name is the name argument value.
+			Name string
+			// Key is the key argument value.
+			Key string
+			// Exchange is the exchange argument value.
+			Exchange string
+			// Args is the args argument value.
+			Args amqp091.Table
+		}
 	}
+	lockCancel                                sync.RWMutex
 	lockClose                                 sync.RWMutex
 	lockConfirm                               sync.RWMutex
 	lockConsume                               sync.RWMutex
+	lockExchangeBind                          sync.RWMutex
 	lockExchangeDeclare                       sync.RWMutex
+	lockExchangeDeclarePassive                sync.RWMutex
+	lockExchangeDelete                        sync.RWMutex
+	lockExchangeUnbind                        sync.RWMutex
 	lockNotifyCancel                          sync.RWMutex
 	lockNotifyClose                           sync.RWMutex
 	lockNotifyReturn                          sync.RWMutex
@@ -215,6 +377,46 @@ type ChannelMock struct {
 	lockQos                                   sync.RWMutex
 	lockQueueBind                             sync.RWMutex
 	lockQueueDeclare                          sync.RWMutex
+	lockQueueDeclarePassive                   sync.RWMutex
+	lockQueueDelete                           sync.RWMutex
+	lockQueuePurge                            sync.RWMutex
+	lockQueueUnbind                           sync.RWMutex
+}
+
+// Cancel calls CancelFunc.
+func (mock *ChannelMock) Cancel(consumer string, noWait bool) error {
+	if mock.CancelFunc == nil {
+		panic("ChannelMock.CancelFunc: method is nil but Channel.Cancel was just called")
+	}
+	callInfo := struct {
+		Consumer string
+		NoWait   bool
+	}{
+		Consumer: consumer,
+		NoWait:   noWait,
+	}
+	mock.lockCancel.Lock()
+	mock.calls.Cancel = append(mock.calls.Cancel, callInfo)
+	mock.lockCancel.Unlock()
+	return mock.CancelFunc(consumer, noWait)
+}
+
+// CancelCalls gets all the calls that were made to Cancel.
+// Check the length with:
+//
+//	len(mockedChannel.CancelCalls())
+func (mock *ChannelMock) CancelCalls() []struct {
+	Consumer string
+	NoWait   bool
+} {
+	var calls []struct {
+		Consumer string
+		NoWait   bool
+	}
+	mock.lockCancel.RLock()
+	calls = mock.calls.Cancel
+	mock.lockCancel.RUnlock()
+	return calls
 }
 
 // Close calls CloseFunc.
@@ -332,6 +534,54 @@ func (mock *ChannelMock) ConsumeCalls() []struct {
 	return calls
 }
 
+// ExchangeBind calls ExchangeBindFunc.
+func (mock *ChannelMock) ExchangeBind(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+	if mock.ExchangeBindFunc == nil {
+		panic("ChannelMock.ExchangeBindFunc: method is nil but Channel.ExchangeBind was just called")
+	}
+	callInfo := struct {
+		Destination string
+		Key         string
+		Source      string
+		NoWait      bool
+		Args        amqp091.Table
+	}{
+		Destination: destination,
+		Key:         key,
+		Source:      source,
+		NoWait:      noWait,
+		Args:        args,
+	}
+	mock.lockExchangeBind.Lock()
+	mock.calls.ExchangeBind = append(mock.calls.ExchangeBind, callInfo)
+	mock.lockExchangeBind.Unlock()
+	return mock.ExchangeBindFunc(destination, key, source, noWait, args)
+}
+
+// ExchangeBindCalls gets all the calls that were made to ExchangeBind.
+// Check the length with:
+//
+//	len(mockedChannel.ExchangeBindCalls())
+func (mock *ChannelMock) ExchangeBindCalls() []struct {
+	Destination string
+	Key         string
+	Source      string
+	NoWait      bool
+	Args        amqp091.Table
+} {
+	var calls []struct {
+		Destination string
+		Key         string
+		Source      string
+		NoWait      bool
+		Args        amqp091.Table
+	}
+	mock.lockExchangeBind.RLock()
+	calls = mock.calls.ExchangeBind
+	mock.lockExchangeBind.RUnlock()
+	return calls
+}
+
 // ExchangeDeclare calls ExchangeDeclareFunc.
 func (mock *ChannelMock) ExchangeDeclare(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
 	if mock.ExchangeDeclareFunc == nil {
@@ -388,6 +638,150 @@ func (mock *ChannelMock) ExchangeDeclareCalls() []struct {
 	return calls
 }
 
+// ExchangeDeclarePassive calls ExchangeDeclarePassiveFunc.
+func (mock *ChannelMock) ExchangeDeclarePassive(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+	if mock.ExchangeDeclarePassiveFunc == nil {
+		panic("ChannelMock.ExchangeDeclarePassiveFunc: method is nil but Channel.ExchangeDeclarePassive was just called")
+	}
+	callInfo := struct {
+		Name       string
+		Kind       string
+		Durable    bool
+		AutoDelete bool
+		Internal   bool
+		NoWait     bool
+		Args       amqp091.Table
+	}{
+		Name:       name,
+		Kind:       kind,
+		Durable:    durable,
+		AutoDelete: autoDelete,
+		Internal:   internal,
+		NoWait:     noWait,
+		Args:       args,
+	}
+	mock.lockExchangeDeclarePassive.Lock()
+	mock.calls.ExchangeDeclarePassive = append(mock.calls.ExchangeDeclarePassive, callInfo)
+	mock.lockExchangeDeclarePassive.Unlock()
+	return mock.ExchangeDeclarePassiveFunc(name, kind, durable, autoDelete, internal, noWait, args)
+}
+
+// ExchangeDeclarePassiveCalls gets all the calls that were made to ExchangeDeclarePassive.
+// Check the length with:
+//
+//	len(mockedChannel.ExchangeDeclarePassiveCalls())
+func (mock *ChannelMock) ExchangeDeclarePassiveCalls() []struct {
+	Name       string
+	Kind       string
+	Durable    bool
+	AutoDelete bool
+	Internal   bool
+	NoWait     bool
+	Args       amqp091.Table
+} {
+	var calls []struct {
+		Name       string
+		Kind       string
+		Durable    bool
+		AutoDelete bool
+		Internal   bool
+		NoWait     bool
+		Args       amqp091.Table
+	}
+	mock.lockExchangeDeclarePassive.RLock()
+	calls = mock.calls.ExchangeDeclarePassive
+	mock.lockExchangeDeclarePassive.RUnlock()
+	return calls
+}
+
+// ExchangeDelete calls ExchangeDeleteFunc.
+func (mock *ChannelMock) ExchangeDelete(name string, ifUnused bool, noWait bool) error {
+	if mock.ExchangeDeleteFunc == nil {
+		panic("ChannelMock.ExchangeDeleteFunc: method is nil but Channel.ExchangeDelete was just called")
+	}
+	callInfo := struct {
+		Name     string
+		IfUnused bool
+		NoWait   bool
+	}{
+		Name:     name,
+		IfUnused: ifUnused,
+		NoWait:   noWait,
+	}
+	mock.lockExchangeDelete.Lock()
+	mock.calls.ExchangeDelete = append(mock.calls.ExchangeDelete, callInfo)
+	mock.lockExchangeDelete.Unlock()
+	return mock.ExchangeDeleteFunc(name, ifUnused, noWait)
+}
+
+// ExchangeDeleteCalls gets all the calls that were made to ExchangeDelete.
+// Check the length with:
+//
+//	len(mockedChannel.ExchangeDeleteCalls())
+func (mock *ChannelMock) ExchangeDeleteCalls() []struct {
+	Name     string
+	IfUnused bool
+	NoWait   bool
+} {
+	var calls []struct {
+		Name     string
+		IfUnused bool
+		NoWait   bool
+	}
+	mock.lockExchangeDelete.RLock()
+	calls = mock.calls.ExchangeDelete
+	mock.lockExchangeDelete.RUnlock()
+	return calls
+}
+
+// ExchangeUnbind calls ExchangeUnbindFunc.
+func (mock *ChannelMock) ExchangeUnbind(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+	if mock.ExchangeUnbindFunc == nil {
+		panic("ChannelMock.ExchangeUnbindFunc: method is nil but Channel.ExchangeUnbind was just called")
+	}
+	callInfo := struct {
+		Destination string
+		Key         string
+		Source      string
+		NoWait      bool
+		Args        amqp091.Table
+	}{
+		Destination: destination,
+		Key:         key,
+		Source:      source,
+		NoWait:      noWait,
+		Args:        args,
+	}
+	mock.lockExchangeUnbind.Lock()
+	mock.calls.ExchangeUnbind = append(mock.calls.ExchangeUnbind, callInfo)
+	mock.lockExchangeUnbind.Unlock()
+	return mock.ExchangeUnbindFunc(destination, key, source, noWait, args)
+}
+
+// ExchangeUnbindCalls gets all the calls that were made to ExchangeUnbind.
+// Check the length with:
+//
+//	len(mockedChannel.ExchangeUnbindCalls())
+func (mock *ChannelMock) ExchangeUnbindCalls() []struct {
+	Destination string
+	Key         string
+	Source      string
+	NoWait      bool
+	Args        amqp091.Table
+} {
+	var calls []struct {
+		Destination string
+		Key         string
+		Source      string
+		NoWait      bool
+		Args        amqp091.Table
+	}
+	mock.lockExchangeUnbind.RLock()
+	calls = mock.calls.ExchangeUnbind
+	mock.lockExchangeUnbind.RUnlock()
+	return calls
+}
+
 // NotifyCancel calls NotifyCancelFunc.
 func (mock *ChannelMock) NotifyCancel(stringCh chan string) chan string {
 	if mock.NotifyCancelFunc == nil {
@@ -675,3 +1069,179 @@ func (mock *ChannelMock) QueueDeclareCalls() []struct {
 	mock.lockQueueDeclare.RUnlock()
 	return calls
 }
+
+// QueueDeclarePassive calls QueueDeclarePassiveFunc.
+func (mock *ChannelMock) QueueDeclarePassive(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+	if mock.QueueDeclarePassiveFunc == nil {
+		panic("ChannelMock.QueueDeclarePassiveFunc: method is nil but Channel.QueueDeclarePassive was just called")
+	}
+	callInfo := struct {
+		Name       string
+		Durable    bool
+		AutoDelete bool
+		Exclusive  bool
+		NoWait     bool
+		Args       amqp091.Table
+	}{
+		Name:       name,
+		Durable:    durable,
+		AutoDelete: autoDelete,
+		Exclusive:  exclusive,
+		NoWait:     noWait,
+		Args:       args,
+	}
+	mock.lockQueueDeclarePassive.Lock()
+	mock.calls.QueueDeclarePassive = append(mock.calls.QueueDeclarePassive, callInfo)
+	mock.lockQueueDeclarePassive.Unlock()
+	return mock.QueueDeclarePassiveFunc(name, durable, autoDelete, exclusive, noWait, args)
+}
+
+// QueueDeclarePassiveCalls gets all the calls that were made to QueueDeclarePassive.
+// Check the length with:
+//
+//	len(mockedChannel.QueueDeclarePassiveCalls())
+func (mock *ChannelMock) QueueDeclarePassiveCalls() []struct {
+	Name       string
+	Durable    bool
+	AutoDelete bool
+	Exclusive  bool
+	NoWait     bool
+	Args       amqp091.Table
+} {
+	var calls []struct {
+		Name       string
+		Durable    bool
+		AutoDelete bool
+		Exclusive  bool
+		NoWait     bool
+		Args       amqp091.Table
+	}
+	mock.lockQueueDeclarePassive.RLock()
+	calls = mock.calls.QueueDeclarePassive
+	mock.lockQueueDeclarePassive.RUnlock()
+	return calls
+}
+
+// QueueDelete calls QueueDeleteFunc.
+func (mock *ChannelMock) QueueDelete(name string, ifUnused bool, ifEmpty bool, noWait bool) (int, error) {
+	if mock.QueueDeleteFunc == nil {
+		panic("ChannelMock.QueueDeleteFunc: method is nil but Channel.QueueDelete was just called")
+	}
+	callInfo := struct {
+		Name     string
+		IfUnused bool
+		IfEmpty  bool
+		NoWait   bool
+	}{
+		Name:     name,
+		IfUnused: ifUnused,
+		IfEmpty:  ifEmpty,
+		NoWait:   noWait,
+	}
+	mock.lockQueueDelete.Lock()
+	mock.calls.QueueDelete = append(mock.calls.QueueDelete, callInfo)
+	mock.lockQueueDelete.Unlock()
+	return mock.QueueDeleteFunc(name, ifUnused, ifEmpty, noWait)
+}
+
+// QueueDeleteCalls gets all the calls that were made to QueueDelete.
+// Check the length with:
+//
+//	len(mockedChannel.QueueDeleteCalls())
+func (mock *ChannelMock) QueueDeleteCalls() []struct {
+	Name     string
+	IfUnused bool
+	IfEmpty  bool
+	NoWait   bool
+} {
+	var calls []struct {
+		Name     string
+		IfUnused bool
+		IfEmpty  bool
+		NoWait   bool
+	}
+	mock.lockQueueDelete.RLock()
+	calls = mock.calls.QueueDelete
+	mock.lockQueueDelete.RUnlock()
+	return calls
+}
+
+// QueuePurge calls QueuePurgeFunc.
+func (mock *ChannelMock) QueuePurge(name string, noWait bool) (int, error) {
+	if mock.QueuePurgeFunc == nil {
+		panic("ChannelMock.QueuePurgeFunc: method is nil but Channel.QueuePurge was just called")
+	}
+	callInfo := struct {
+		Name   string
+		NoWait bool
+	}{
+		Name:   name,
+		NoWait: noWait,
+	}
+	mock.lockQueuePurge.Lock()
+	mock.calls.QueuePurge = append(mock.calls.QueuePurge, callInfo)
+	mock.lockQueuePurge.Unlock()
+	return mock.QueuePurgeFunc(name, noWait)
+}
+
+// QueuePurgeCalls gets all the calls that were made to QueuePurge.
+// Check the length with:
+//
+//	len(mockedChannel.QueuePurgeCalls())
+func (mock *ChannelMock) QueuePurgeCalls() []struct {
+	Name   string
+	NoWait bool
+} {
+	var calls []struct {
+		Name   string
+		NoWait bool
+	}
+	mock.lockQueuePurge.RLock()
+	calls = mock.calls.QueuePurge
+	mock.lockQueuePurge.RUnlock()
+	return calls
+}
+
+// QueueUnbind calls QueueUnbindFunc.
+func (mock *ChannelMock) QueueUnbind(name string, key string, exchange string, args amqp091.Table) error {
+	if mock.QueueUnbindFunc == nil {
+		panic("ChannelMock.QueueUnbindFunc: method is nil but Channel.QueueUnbind was just called")
+	}
+	callInfo := struct {
+		Name     string
+		Key      string
+		Exchange string
+		Args     amqp091.Table
+	}{
+		Name:     name,
+		Key:      key,
+		Exchange: exchange,
+		Args:     args,
+	}
+	mock.lockQueueUnbind.Lock()
+	mock.calls.QueueUnbind = append(mock.calls.QueueUnbind, callInfo)
+	mock.lockQueueUnbind.Unlock()
+	return mock.QueueUnbindFunc(name, key, exchange, args)
+}
+
+// QueueUnbindCalls gets all the calls that were made to QueueUnbind.
+// Check the length with:
+//
+//	len(mockedChannel.QueueUnbindCalls())
+func (mock *ChannelMock) QueueUnbindCalls() []struct {
+	Name     string
+	Key      string
+	Exchange string
+	Args     amqp091.Table
+} {
+	var calls []struct {
+		Name     string
+		Key      string
+		Exchange string
+		Args     amqp091.Table
+	}
+	mock.lockQueueUnbind.RLock()
+	calls = mock.calls.QueueUnbind
+	mock.lockQueueUnbind.RUnlock()
+	return calls
+}
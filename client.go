@@ -3,7 +3,9 @@ package amqpx
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rabbitmq/amqp091-go"
@@ -16,7 +18,7 @@ const (
 
 // A Client represents connection to rabbitmq.
 type Client struct {
-	dialer      dialer
+	dialer      Dialer
 	mx          sync.RWMutex
 	amqpConn    Connection
 	marshaler   Marshaler
@@ -25,16 +27,39 @@ type Client struct {
 	notifyClose chan *amqp091.Error
 	wg          *sync.WaitGroup
 	done        context.Context
-	cancel      context.CancelFunc
+	cancel      context.CancelCauseFunc
+	state       atomic.Int32
+	hooks       hooks
+	blocking    *blocking
+	consumers   []*consumer
+	publishing  *inflight
+	ready       chan struct{}
+	readyOnce   sync.Once
+	reconnected *signal
+	credentials *credentialsSource
+	topology    Topology
 
-	logger      LogFunc
+	logger      *slog.Logger
+	backoff     Backoff
 	wrapConsume []ConsumeInterceptor
 	wrapPublish []PublishInterceptor
 }
 
 // Connect creates a connection.
+// The dial is limited by the connect timeout, the default is 4s.
 func Connect(opts ...ClientOption) (*Client, error) {
+	return connect(context.Background(), defaultConnectTimeout, opts)
+}
+
+// ConnectContext creates a connection.
+// The dial is limited by ctx and the connect timeout if it is set.
+func ConnectContext(ctx context.Context, opts ...ClientOption) (*Client, error) {
+	return connect(ctx, 0, opts)
+}
+
+func connect(ctx context.Context, timeout time.Duration, opts []ClientOption) (*Client, error) {
 	opt := newClientOptions()
+	opt.connectTimeout = timeout
 	for _, o := range opts {
 		o(&opt)
 	}
@@ -43,98 +68,447 @@ func Connect(opts ...ClientOption) (*Client, error) {
 		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultConnectTimeout)
-	defer cancel()
-
-	amqpConn, err := opt.dialer.Dial(ctx)
-	if err != nil {
-		return nil, err
-	}
-
 	conn := &Client{
 		dialer:      opt.dialer,
-		amqpConn:    amqpConn,
 		marshaler:   opt.marshaler,
 		unmarshaler: opt.unmarshaler,
-		notifyClose: amqpConn.NotifyClose(make(chan *amqp091.Error, 1)),
 		wg:          &sync.WaitGroup{},
+		ready:       make(chan struct{}),
+		reconnected: &signal{},
 		logger:      opt.logger,
+		backoff:     opt.backoff,
+		hooks:       opt.hooks,
+		blocking:    newBlocking(),
+		publishing:  &inflight{},
+		credentials: opt.credentials,
+		topology:    opt.topology,
 		wrapConsume: opt.wrapConsume,
 		wrapPublish: opt.wrapPublish,
 	}
-	conn.done, conn.cancel = context.WithCancel(context.Background())
-	go conn.loop()
+	conn.done, conn.cancel = context.WithCancelCause(context.Background())
+
+	// the first dial is made by the loop
+	if opt.lazy {
+		go conn.loop()
+		return conn, nil
+	}
+
+	if opt.connectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opt.connectTimeout)
+		defer cancel()
+	}
+
+	amqpConn, err := opt.dialer.Dial(ctx)
+	if err != nil {
+		conn.cancel(err)
+		return nil, err
+	}
+
+	if err := conn.topology.declare(amqpConn); err != nil {
+		amqpConn.Close()
+		conn.cancel(err)
+		return nil, fmt.Errorf("amqpx: %w", err)
+	}
 
+	conn.connected(amqpConn)
+	go conn.loop()
 	return conn, nil
 }
 
+// State returns the current state of the client.
+func (c *Client) State() State {
+	return State(c.state.Load())
+}
+
+// Done returns a channel that's closed when the client is closed or the connection is permanently lost.
+func (c *Client) Done() <-chan struct{} {
+	return c.done.Done()
+}
+
+// Err returns nil if Done is not yet closed.
+// If Done is closed, Err returns ErrClosed when the client is closed
+// or the error of the last dial when the connection is permanently lost.
+func (c *Client) Err() error {
+	if c.done.Err() == nil {
+		return nil
+	}
+	return context.Cause(c.done)
+}
+
 // IsConnOpen returns true if the connection is open.
 func (c *Client) IsConnOpen() bool {
 	c.mx.RLock()
 	defer c.mx.RUnlock()
-	return !c.amqpConn.IsClosed()
+	return c.amqpConn != nil && !c.amqpConn.IsClosed()
+}
+
+// IsBlocked returns true if the server blocked the connection (ex: memory or disk alarms).
+// The publishers wait until the connection is unblocked unless FailOnBlocked is set.
+func (c *Client) IsBlocked() bool {
+	return c.blocking.isBlocked()
 }
 
-// NewConsumer creates a consumer.
-func (c *Client) NewConsumer(queue string, fn HandlerValue, opts ...ConsumerOption) error {
+// NotifyBlocked registers a listener for blocking and unblocking of the connection.
+// The notification is dropped if the channel is full, so the buffered channel should be used.
+// The channel is closed when the client is closed.
+func (c *Client) NotifyBlocked(ch chan Blocking) chan Blocking {
+	return c.blocking.notify(ch)
+}
+
+// NewConsumer creates a consumer and returns its handle.
+func (c *Client) NewConsumer(queue string, fn HandlerValue, opts ...ConsumerOption) (*Consumer, error) {
 	opt := consumerOptions{
-		interceptor: c.wrapConsume,
-		unmarshaler: c.unmarshaler,
+		interceptor:   c.wrapConsume,
+		unmarshaler:   c.unmarshaler,
+		backoff:       c.backoff,
+		panicAction:   Reject,
+		timeoutAction: Nack,
 	}
 	for _, o := range opts {
 		o(&opt)
 	}
 
 	if err := opt.validate(fn); err != nil {
-		return fmt.Errorf("amqpx: queue %q consumer-tag %q: %s", queue, opt.tag, err)
+		return nil, fmt.Errorf("amqpx: queue %q consumer-tag %q: %s", queue, opt.tag, err)
 	}
 
-	fn.init(opt.unmarshaler)
+	fn.init(opt.unmarshaler, opt.errorPolicy)
 	cons := &consumer{
-		conn:  c.conn,
-		queue: queue,
-		tag:   opt.tag,
-		opts:  opt.channel,
-		log:   c.logger,
-		limit: semaphore.NewWeighted(int64(opt.concurrency)),
-		wg:    c.wg,
-		fn:    fn.serve,
-		done:  c.done,
+		conn:      c.conn,
+		queue:     queue,
+		tag:       opt.tag,
+		opts:      opt.channel,
+		backoff:   opt.backoff,
+		onActive:  opt.onActive,
+		log:       c.logger.With(LogKeyQueue, queue, LogKeyConsumerTag, opt.tag),
+		limit:     semaphore.NewWeighted(int64(opt.concurrency)),
+		wg:        c.wg,
+		fn:        fn.serve,
+		ready:     c.ready,
+		connected: c.reconnected.wait,
+		done:      c.done,
+		stopped:   make(chan struct{}),
+		pause:     make(chan struct{}, 1),
+		inflight:  &inflight{},
+	}
+	if b, ok := fn.(batchHandler); ok {
+		cons.batch = newBatch(b)
+		if !opt.noRecover {
+			cons.batch.fn = recoverBatch(cons.batch.fn, opt.panicAction)
+		}
+	}
+	if opt.channel.retry != nil {
+		if queue == "" {
+			return nil, fmt.Errorf("amqpx: queue %q consumer-tag %q: %s", queue, opt.tag, errRetryQueue)
+		}
+		cons.retry = newRetrier(queue, *opt.channel.retry)
 	}
+	if opt.handlerTimeout > 0 || opt.slowThreshold > 0 {
+		cons.watchdog = &watchdog{
+			timeout: opt.handlerTimeout,
+			action:  opt.timeoutAction,
+			slow:    opt.slowThreshold,
+			stats:   &cons.stats,
+		}
+	}
+	if opt.channel.streamOffset != nil {
+		cons.stream = &streamCursor{start: *opt.channel.streamOffset}
+	}
+	cons.stop, cons.cancelStop = context.WithCancel(c.done)
+	cons.delivery.init(c.done, nil)
 
 	// wrap the end fn with the interceptor chain.
+	// The panic of the handler is recovered inside the chain, so the interceptors observe it,
+	// and the panic of the interceptors is recovered outside.
+	if !opt.noRecover {
+		cons.fn = recoverConsume(cons.fn, opt.panicAction)
+	}
 	if len(opt.interceptor) != 0 {
-		cons.fn = opt.interceptor[len(opt.interceptor)-1](cons.fn)
-		for i := len(opt.interceptor) - 2; i >= 0; i-- {
-			cons.fn = opt.interceptor[i](cons.fn)
+		chain := func(next ConsumeFunc) ConsumeFunc {
+			next = opt.interceptor[len(opt.interceptor)-1](next)
+			for i := len(opt.interceptor) - 2; i >= 0; i-- {
+				next = opt.interceptor[i](next)
+			}
+
+			if !opt.noRecover {
+				next = recoverConsume(next, opt.panicAction)
+			}
+			return next
+		}
+
+		cons.fn = chain(cons.fn)
+		if cons.batch != nil {
+			cons.batch.fn = interceptBatch(cons.batch.fn, chain)
 		}
 	}
 
-	if err := cons.initChannel(); err != nil {
-		return fmt.Errorf("amqpx: queue %q consumer-tag %q: %s", cons.queue, cons.tag, err)
+	// the consumer is activated once the connection is opened in lazy mode
+	if c.State() != StateConnecting {
+		if err := cons.initChannel(); err != nil {
+			cons.cancelStop()
+			return nil, fmt.Errorf("amqpx: queue %q consumer-tag %q: %s", cons.queue, cons.tag, err)
+		}
 	}
+
+	c.mx.Lock()
+	c.consumers = append(c.consumers, cons)
+	c.mx.Unlock()
+
 	go cons.serve()
+	return &Consumer{c: cons, remove: func() { c.removeConsumer(cons) }}, nil
+}
+
+// removeConsumer removes the cancelled consumer, so it is not waited on shutdown.
+func (c *Client) removeConsumer(cons *consumer) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	for i, v := range c.consumers {
+		if v == cons {
+			c.consumers = append(c.consumers[:i:i], c.consumers[i+1:]...)
+			return
+		}
+	}
+}
+
+// UnbindQueue removes the routing keys of the queue binding.
+// The binding is removed from the topology, so it is not re-declared on reconnect.
+func (c *Client) UnbindQueue(b QueueBind) error {
+	err := c.withChannel(func(channel Channel) error {
+		for _, k := range b.RoutingKey {
+			if err := channel.QueueUnbind(b.Queue, k, b.Exchange, b.Args); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("amqpx: unbind queue %q exchange %q: %w", b.Queue, b.Exchange, err)
+	}
+
+	c.mx.Lock()
+	c.topology = c.topology.unbindQueue(b)
+	c.mx.Unlock()
+	return nil
+}
+
+// UnbindExchange removes the routing keys of the exchange to exchange binding.
+// The binding is removed from the topology, so it is not re-declared on reconnect.
+func (c *Client) UnbindExchange(b ExchangeBind) error {
+	err := c.withChannel(func(channel Channel) error {
+		for _, k := range b.RoutingKey {
+			if err := channel.ExchangeUnbind(b.Destination, k, b.Source, b.NoWait, b.Args); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("amqpx: unbind exchange %q -> %q: %w", b.Source, b.Destination, err)
+	}
+
+	c.mx.Lock()
+	c.topology = c.topology.unbindExchange(b)
+	c.mx.Unlock()
 	return nil
 }
 
+// withChannel runs fn on a short-lived channel of the connection.
+func (c *Client) withChannel(fn func(channel Channel) error) error {
+	conn := c.conn()
+	if conn == nil || conn.IsClosed() {
+		return errConnClosed
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("create channel: %w", err)
+	}
+	defer channel.Close()
+
+	return fn(channel)
+}
+
 // Close closes Connection.
 // Waits all consumers.
 func (c *Client) Close() {
-	c.cancel()
+	c.setClosed()
+
+	// the consumers stop receiving before the handlers are waited
+	c.mx.RLock()
+	consumers := c.consumers
+	c.mx.RUnlock()
+	for _, v := range consumers {
+		<-v.stopped
+	}
 	c.wg.Wait()
 
+	c.closeConn()
+}
+
+// Shutdown gracefully closes Connection.
+//
+// It cancels all consumers so no new deliveries arrive, requeues the prefetched deliveries
+// which have not been started, waits the in-flight handlers with their original contexts,
+// waits the outstanding publishes including confirms and then closes the connection.
+// If ctx is done before, the connection is force closed and ctx error is returned.
+// The handlers abandoned by the handler timeout are not waited, their number is logged.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.mx.RLock()
+	consumers := c.consumers
+	c.mx.RUnlock()
+
+	for _, v := range consumers {
+		v.cancelStop()
+	}
+
+	err := waitContext(ctx, func() {
+		for _, v := range consumers {
+			<-v.stopped
+		}
+		c.wg.Wait()
+	})
+	if err == nil {
+		err = c.publishing.wait(ctx)
+	}
+
+	var abandoned int64
+	for _, v := range consumers {
+		abandoned += v.stats.abandoned.Load()
+	}
+	if abandoned != 0 {
+		c.logger.Warn("abandoned handlers are not waited", "count", abandoned)
+	}
+
+	if err != nil {
+		c.setClosed()
+		c.closeConn()
+		return err
+	}
+
+	c.Close()
+	return nil
+}
+
+// setClosed cancels the client under the lock, so the connection dialed after is not set.
+func (c *Client) setClosed() {
 	c.mx.Lock()
 	defer c.mx.Unlock()
-	c.amqpConn.Close()
+
+	c.cancel(ErrClosed)
+	c.state.Store(int32(StateClosed))
 }
 
-func (c *Client) setConn(conn Connection) {
+func (c *Client) closeConn() {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
-	c.amqpConn.Close()
+	if c.amqpConn != nil {
+		c.amqpConn.Close()
+	}
+}
+
+// connected sets the opened connection, it returns false if the client is closed.
+func (c *Client) connected(conn Connection) bool {
+	if !c.setConn(conn) {
+		return false
+	}
+
+	c.readyOnce.Do(func() { close(c.ready) })
+	c.reconnected.notify()
+	c.hooks.onConnect()
+
+	if c.credentials != nil {
+		go c.refreshCredentials(conn, conn.NotifyClose(make(chan *amqp091.Error, 1)))
+	}
+	return true
+}
+
+// refreshCredentials refreshes the connection before the credentials expire.
+func (c *Client) refreshCredentials(conn Connection, notifyClose chan *amqp091.Error) {
+	for {
+		creds := c.credentials.last()
+		if creds.ExpiresAt.IsZero() {
+			return
+		}
+
+		timer := time.NewTimer(refreshDelay(creds.ExpiresAt))
+		select {
+		case <-c.done.Done():
+			timer.Stop()
+			return
+
+		case <-notifyClose:
+			timer.Stop()
+			return
+
+		case <-timer.C:
+		}
+
+		next, err := c.credentials.get(c.done)
+		if err != nil {
+			c.logger.Error("refresh credentials", LogKeyError, err)
+
+			// retry while the credentials are valid
+			if time.Now().Before(creds.ExpiresAt) {
+				continue
+			}
+
+			conn.Close()
+			return
+		}
+
+		if next.Username == creds.Username {
+			if err := conn.UpdateSecret(next.Password, "credentials refresh"); err == nil {
+				continue
+			}
+			c.logger.Error("refresh credentials: update secret", LogKeyError, err)
+		}
+
+		// reconnect with the new credentials
+		conn.Close()
+		return
+	}
+}
+
+func (c *Client) setConn(conn Connection) bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	// the connection is dialed after the client is closed
+	if c.done.Err() != nil {
+		conn.Close()
+		return false
+	}
+
+	if c.amqpConn != nil {
+		c.amqpConn.Close()
+	}
 	c.amqpConn = conn
 	c.notifyClose = c.amqpConn.NotifyClose(make(chan *amqp091.Error, 1))
+
+	c.state.Store(int32(StateConnected))
+
+	// a new connection is not blocked
+	c.blocking.set(Blocking{Active: false})
+	go c.watchBlocked(conn, conn.NotifyBlocked(make(chan Blocking, 1)))
+	return true
+}
+
+func (c *Client) watchBlocked(conn Connection, notify chan Blocking) {
+	for v := range notify {
+		if c.conn() != conn {
+			return
+		}
+
+		if v.Active {
+			c.logger.Warn("connection is blocked", "reason", v.Reason)
+		} else {
+			c.logger.Info("connection is unblocked")
+		}
+		c.blocking.set(v)
+	}
 }
 
 func (c *Client) conn() Connection {
@@ -144,17 +518,189 @@ func (c *Client) conn() Connection {
 }
 
 func (c *Client) loop() {
-	for {
+	defer c.blocking.close()
+
+	if c.State() == StateConnecting {
+		conn, err := c.dial(c.done)
+		if err != nil {
+			if c.done.Err() == nil {
+				c.fail(err)
+			}
+			return
+		}
+
+		if !c.connected(conn) {
+			return
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
 		select {
 		case <-c.done.Done():
 			return
 
-		case <-c.notifyClose:
-			conn, err := c.dialer.Dial(c.done)
+		case amqpErr := <-c.notifyClose:
+			// the connection is closed by the client
+			if c.done.Err() != nil {
+				return
+			}
+
+			c.state.Store(int32(StateReconnecting))
+			var err error
+			if amqpErr != nil {
+				err = amqpErr
+			}
+			c.logger.Warn("connection is lost, reconnecting", LogKeyError, err)
+			c.hooks.onDisconnect(err)
+
+			conn, err := c.dial(c.done)
 			if err != nil {
+				if c.done.Err() == nil {
+					c.fail(err)
+				}
 				return
 			}
-			c.setConn(conn)
+			if !c.connected(conn) {
+				return
+			}
+			c.hooks.onReconnect(attempt)
+		}
+	}
+}
+
+// dial opens the connection and declares the topology before the consumers and publishers resume.
+// The failed declaration is retried on a new connection with the backoff.
+func (c *Client) dial(ctx context.Context) (Connection, error) {
+	for attempt := 1; ; attempt++ {
+		conn, err := c.dialer.Dial(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		c.mx.RLock()
+		topology := c.topology
+		c.mx.RUnlock()
+
+		if err = topology.declare(conn); err == nil {
+			return conn, nil
+		}
+		conn.Close()
+		c.logger.Error("declare topology", LogKeyAttempt, attempt, LogKeyError, err)
+
+		delay, ok := c.backoff.Next(attempt, err)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errMaxAttempts, err)
 		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s: %w", err, ctx.Err())
+
+		case <-time.After(delay):
+		}
+	}
+}
+
+// fail stops the client when the connection is permanently lost.
+func (c *Client) fail(err error) {
+	c.logger.Error("connection is permanently lost", LogKeyError, err)
+	c.state.Store(int32(StateFailed))
+	c.cancel(err)
+	c.hooks.onFatal(err)
+}
+
+// An inflight represents counter of the operations in progress.
+type inflight struct {
+	mx   sync.Mutex
+	n    int
+	idle chan struct{}
+}
+
+func (i *inflight) add() {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	if i.n == 0 {
+		i.idle = make(chan struct{})
+	}
+	i.n++
+}
+
+func (i *inflight) done() {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	i.n--
+	if i.n == 0 {
+		close(i.idle)
+	}
+}
+
+func (i *inflight) len() int {
+	i.mx.Lock()
+	defer i.mx.Unlock()
+
+	return i.n
+}
+
+// wait waits until all operations are done or ctx is done.
+func (i *inflight) wait(ctx context.Context) error {
+	i.mx.Lock()
+	if i.n == 0 {
+		i.mx.Unlock()
+		return nil
+	}
+	idle := i.idle
+	i.mx.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// A signal notifies the waiters of the event which repeats, ex: the connection is opened.
+type signal struct {
+	mx sync.Mutex
+	ch chan struct{}
+}
+
+// wait returns the channel which is closed on the next event.
+func (s *signal) wait() <-chan struct{} {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+func (s *signal) notify() {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+}
+
+func waitContext(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
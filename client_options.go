@@ -2,8 +2,12 @@ package amqpx
 
 import (
 	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/rabbitmq/amqp091-go"
 )
@@ -19,16 +23,26 @@ type Interceptor interface {
 type ClientOption func(*clientOptions)
 
 type clientOptions struct {
-	uri    amqp091.URI
-	config amqp091.Config
+	uri         amqp091.URI
+	uris        []amqp091.URI
+	hosts       []string
+	randomHosts bool
+	config      amqp091.Config
+
+	connectTimeout time.Duration
+	lazy           bool
 
 	marshaler   Marshaler
 	unmarshaler map[string]Unmarshaler
 	wrapConsume []ConsumeInterceptor
 	wrapPublish []PublishInterceptor
-	logger      LogFunc
+	logger      *slog.Logger
+	backoff     Backoff
+	hooks       hooks
+	credentials *credentialsSource
+	topology    Topology
 
-	dialer dialer
+	dialer Dialer
 	err    error
 }
 
@@ -53,6 +67,29 @@ func ApplyURI(s string) ClientOption {
 	}
 }
 
+// ApplyURIs sets amqp URIs of the cluster nodes.
+// Each URI is dialed as it is including its scheme, credentials and vhost,
+// the first URI is also applied as ApplyURI does. It overrides SetHosts.
+func ApplyURIs(s ...string) ClientOption {
+	return func(o *clientOptions) {
+		uris := make([]amqp091.URI, 0, len(s))
+		for _, v := range s {
+			u, err := amqp091.ParseURI(v)
+			if err != nil {
+				o.err = err
+				return
+			}
+			uris = append(uris, u)
+		}
+
+		if len(uris) != 0 {
+			o.uri = uris[0]
+		}
+		o.uris = uris
+		o.hosts = nil
+	}
+}
+
 // SetConfig sets amqp config.
 func SetConfig(c Config) ClientOption {
 	return func(o *clientOptions) {
@@ -63,13 +100,43 @@ func SetConfig(c Config) ClientOption {
 // SetHost sets host and port.
 func SetHost(h string) ClientOption {
 	return func(o *clientOptions) {
-		o.uri.Host = h
-		hp := strings.Split(h, ":")
-		if p, err := strconv.Atoi(hp[len(hp)-1]); err == nil {
-			o.uri.Host = strings.Join(hp[:len(hp)-1], ":")
-			o.uri.Port = p
-			return
-		}
+		setHost(&o.uri, h)
+	}
+}
+
+// SetHosts sets hosts and ports of the cluster nodes.
+// The client connects to the first available node and moves to another one on reconnect when the node is lost.
+// The default is the single host. It overrides ApplyURIs.
+func SetHosts(h ...string) ClientOption {
+	return func(o *clientOptions) {
+		o.hosts = h
+		o.uris = nil
+	}
+}
+
+// RandomizeHosts sets randomized order of dialing the cluster nodes.
+// The default is round-robin.
+func RandomizeHosts() ClientOption {
+	return func(o *clientOptions) {
+		o.randomHosts = true
+	}
+}
+
+// SetConnectTimeout sets timeout of the first dial.
+// The default is 4s for Connect and no timeout for ConnectContext.
+func SetConnectTimeout(d time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.connectTimeout = d
+	}
+}
+
+// LazyConnect sets lazy mode of the connection.
+// Connect returns immediately and the first dial is made in background,
+// the consumers and publishers can be created up front and activated once the connection is opened.
+// The dial is limited only by backoff policy.
+func LazyConnect() ClientOption {
+	return func(o *clientOptions) {
+		o.lazy = true
 	}
 }
 
@@ -88,6 +155,18 @@ func SetAuth(username, password string) ClientOption {
 	}
 }
 
+// UseCredentials sets provider of the rotating credentials.
+// The provider is consulted on every dial and overrides SetAuth.
+// The connection is refreshed before the credentials expire: the secret of the connection is updated
+// when the username is the same (ex: OAuth2 token), otherwise the client reconnects.
+func UseCredentials(p CredentialsProvider) ClientOption {
+	return func(o *clientOptions) {
+		if p != nil {
+			o.credentials = &credentialsSource{provider: p}
+		}
+	}
+}
+
 // SetTLSConfig sets tls config.
 func SetTLSConfig(t *tls.Config) ClientOption {
 	return func(o *clientOptions) {
@@ -111,12 +190,30 @@ func SetConnectionName(name string) ClientOption {
 	}
 }
 
-// WithLog sets log.
-// The default is stdout.
+// WithLog sets printf log, the records are formatted as "[LEVEL] message key=value".
+// The default is stderr.
 func WithLog(log LogFunc) ClientOption {
 	return func(o *clientOptions) {
-		if o.logger != nil {
-			o.logger = log
+		if log != nil {
+			o.logger = newLogFuncLogger(log)
+		}
+	}
+}
+
+// WithLogger sets structured logger.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(o *clientOptions) {
+		if l != nil {
+			o.logger = l
+		}
+	}
+}
+
+// WithLogHandler sets handler of structured logger.
+func WithLogHandler(h slog.Handler) ClientOption {
+	return func(o *clientOptions) {
+		if h != nil {
+			o.logger = slog.New(h)
 		}
 	}
 }
@@ -152,9 +249,68 @@ func UseMarshaler(m Marshaler) ClientOption {
 	}
 }
 
-func setDialer(d dialer) ClientOption {
+// UseBackoff sets reconnect backoff policy of the connection.
+// It is used by consumers and publishers unless they set own policy,
+// they wait while the client reconnects and spend the attempts only on the channel errors.
+// The default is constant delay 4s without limit of attempts.
+func UseBackoff(b Backoff) ClientOption {
+	return func(o *clientOptions) {
+		if b != nil {
+			o.backoff = b
+		}
+	}
+}
+
+// OnConnect sets hook is called when the connection is opened, including every reconnect.
+// The hook is called synchronously and must not block.
+func OnConnect(fn func()) ClientOption {
 	return func(o *clientOptions) {
-		o.dialer = d
+		o.hooks.connect = fn
+	}
+}
+
+// OnDisconnect sets hook is called when the connection is lost.
+// The err is nil when the connection is closed gracefully.
+// The hook is called synchronously and must not block.
+func OnDisconnect(fn func(err error)) ClientOption {
+	return func(o *clientOptions) {
+		o.hooks.disconnect = fn
+	}
+}
+
+// OnReconnect sets hook is called when the lost connection is opened again,
+// the attempt is the serial number of the reconnect.
+// The hook is called synchronously and must not block.
+func OnReconnect(fn func(attempt int)) ClientOption {
+	return func(o *clientOptions) {
+		o.hooks.reconnect = fn
+	}
+}
+
+// OnFatal sets hook is called when the connection is permanently lost and the client is stopped.
+// The hook is called synchronously and must not block.
+func OnFatal(fn func(err error)) ClientOption {
+	return func(o *clientOptions) {
+		o.hooks.fatal = fn
+	}
+}
+
+// WithDialer sets dialer of the connection.
+// The hosts, credentials and backoff of the dial are not applied to the custom dialer.
+// The default dialer uses amqp091 client.
+func WithDialer(d Dialer) ClientOption {
+	return func(o *clientOptions) {
+		if d != nil {
+			o.dialer = d
+		}
+	}
+}
+
+// DeclareTopology sets topology which is declared on connect and re-declared on every reconnect.
+// The topologies of several options are merged.
+func DeclareTopology(t Topology) ClientOption {
+	return func(o *clientOptions) {
+		o.topology = o.topology.merge(t)
 	}
 }
 
@@ -163,16 +319,85 @@ func (c *clientOptions) validate() error {
 		return c.err
 	}
 
+	if err := c.topology.validate(); err != nil {
+		return fmt.Errorf("amqpx: topology: %w", err)
+	}
+
 	if c.logger == nil {
 		c.logger = defaultLogger
 	}
 
+	if c.backoff == nil {
+		c.backoff = defaultBackoff
+	}
+
 	if c.dialer == nil {
-		c.dialer = &defaultDialer{
-			URI:    c.uri.String(),
-			Config: c.config,
-			log:    c.logger,
-		}
+		d := newDefaultDialer(c.nodes(), c.config, c.randomHosts, c.backoff, c.logger)
+		d.credentials = c.credentials
+		c.dialer = d
 	}
 	return nil
 }
+
+// nodes returns URIs of the cluster nodes.
+func (c *clientOptions) nodes() []amqp091.URI {
+	if len(c.uris) != 0 {
+		return c.uris
+	}
+
+	if len(c.hosts) == 0 {
+		return []amqp091.URI{c.uri}
+	}
+
+	uris := make([]amqp091.URI, len(c.hosts))
+	for i, h := range c.hosts {
+		uris[i] = c.uri
+		setHost(&uris[i], h)
+	}
+	return uris
+}
+
+func setHost(u *amqp091.URI, h string) {
+	host, port, err := net.SplitHostPort(h)
+	if err != nil {
+		// the host without port, the brackets of IPv6 are added by the URI
+		u.Host = strings.TrimSuffix(strings.TrimPrefix(h, "["), "]")
+		return
+	}
+
+	u.Host = host
+	if p, err := strconv.Atoi(port); err == nil {
+		u.Port = p
+	}
+}
+
+type hooks struct {
+	connect    func()
+	disconnect func(error)
+	reconnect  func(int)
+	fatal      func(error)
+}
+
+func (h hooks) onConnect() {
+	if h.connect != nil {
+		h.connect()
+	}
+}
+
+func (h hooks) onDisconnect(err error) {
+	if h.disconnect != nil {
+		h.disconnect(err)
+	}
+}
+
+func (h hooks) onReconnect(attempt int) {
+	if h.reconnect != nil {
+		h.reconnect(attempt)
+	}
+}
+
+func (h hooks) onFatal(err error) {
+	if h.fatal != nil {
+		h.fatal(err)
+	}
+}
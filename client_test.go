@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ const defaultTimeout = time.Second * 2
 func TestClient_ConnectErr(t *testing.T) {
 	t.Parallel()
 
-	_, err := Connect(setDialer(&dialerMock{
+	_, err := Connect(WithDialer(&DialerMock{
 		DialFunc: func(_ context.Context) (Connection, error) {
 			return nil, fmt.Errorf("conn failed")
 		},
@@ -25,6 +26,95 @@ func TestClient_ConnectErr(t *testing.T) {
 	assert.EqualError(t, err, "conn failed")
 }
 
+func TestClient_ConnectContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := ConnectContext(ctx, WithDialer(&DialerMock{
+		DialFunc: func(ctx context.Context) (Connection, error) {
+			return nil, ctx.Err()
+		},
+	}))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_LazyConnect(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	prepared, mock := prep(t)
+	prepared.Close()
+
+	dial := make(chan bool)
+	consume := make(chan bool)
+	dialer := &DialerMock{
+		DialFunc: func(_ context.Context) (Connection, error) {
+			<-dial
+			return mock.Conn, nil
+		},
+	}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		close(consume)
+		return make(chan amqp091.Delivery), nil
+	}
+
+	client, err := Connect(WithDialer(dialer), LazyConnect())
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, StateConnecting, client.State())
+	assert.False(t, client.IsConnOpen())
+	_, err = client.NewConsumer("foo", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }))
+	require.NoError(t, err)
+
+	close(dial)
+	<-consume
+	assert.Equal(t, StateConnected, client.State())
+}
+
+func TestClient_LazyConnectClosed(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	prepared, mock := prep(t)
+	prepared.Close()
+
+	var closed atomic.Bool
+	mock.Conn.CloseFunc = func() error {
+		closed.Store(true)
+		return nil
+	}
+
+	dialing := make(chan bool)
+	dial := make(chan bool)
+	dialer := &DialerMock{
+		DialFunc: func(_ context.Context) (Connection, error) {
+			close(dialing)
+			<-dial
+			return mock.Conn, nil
+		},
+	}
+
+	var connected atomic.Bool
+	client, err := Connect(WithDialer(dialer), LazyConnect(), OnConnect(func() { connected.Store(true) }))
+	require.NoError(t, err)
+	notify := client.NotifyBlocked(make(chan Blocking, 1))
+
+	// the connection is dialed after the client is closed
+	<-dialing
+	client.Close()
+	close(dial)
+	for range notify {
+	}
+
+	assert.True(t, closed.Load())
+	assert.False(t, connected.Load())
+	assert.Equal(t, StateClosed, client.State())
+	assert.False(t, client.IsConnOpen())
+}
+
 func TestClient_Reconnect(t *testing.T) {
 	t.Parallel()
 
@@ -45,8 +135,328 @@ func TestClient_Reconnect(t *testing.T) {
 	}
 }
 
+func TestClient_Topology(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	client, mock := prep(t, DeclareTopology(Topology{Exchanges: []ExchangeDeclare{{Name: "foo", Type: "direct"}}}))
+	defer client.Close()
+	require.Len(t, mock.Channel.ExchangeDeclareCalls(), 1)
+
+	var closed atomic.Bool
+	mock.Conn.IsClosedFunc = func() bool {
+		return closed.Load()
+	}
+	mock.Dialer.DialFunc = func(_ context.Context) (Connection, error) {
+		closed.Store(false)
+		return mock.Conn, nil
+	}
+
+	events := make(chan string, 4)
+	mock.Channel.ExchangeDeclareFunc = func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+		events <- "declare"
+		return nil
+	}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		events <- "consume"
+		return make(chan amqp091.Delivery), nil
+	}
+	_, err := client.NewConsumer("foo", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }),
+		SetConsumerBackoff(ConstantBackoff{Delay: time.Millisecond}))
+	require.NoError(t, err)
+	require.Equal(t, "consume", <-events)
+
+	// the topology is re-declared before the consumer resumes
+	closed.Store(true)
+	mock.Conn.Close()
+	assert.Equal(t, "declare", <-events)
+	assert.Equal(t, "consume", <-events)
+}
+
+func TestClient_TopologyErr(t *testing.T) {
+	t.Parallel()
+
+	conn := &ConnectionMock{
+		ChannelFunc: func() (Channel, error) {
+			return nil, fmt.Errorf("channel failed")
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+
+	_, err := Connect(WithDialer(&DialerMock{
+		DialFunc: func(_ context.Context) (Connection, error) {
+			return conn, nil
+		},
+	}), DeclareTopology(Topology{Queues: []QueueDeclare{{Name: "foo"}}}))
+	assert.EqualError(t, err, "amqpx: topology: create channel: channel failed")
+	assert.Len(t, conn.CloseCalls(), 1)
+}
+
+func TestClient_Unbind(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, DeclareTopology(Topology{
+		QueueBindings:    []QueueBind{{Queue: "foo", Exchange: "events", RoutingKey: []string{"a", "b"}}},
+		ExchangeBindings: []ExchangeBind{{Source: "root", Destination: "events", RoutingKey: []string{"c"}}},
+	}))
+	defer client.Close()
+
+	var got []string
+	channel := &ChannelMock{
+		QueueUnbindFunc: func(name string, key string, exchange string, args amqp091.Table) error {
+			got = append(got, "queue "+exchange+" "+key+" "+name)
+			return nil
+		},
+		ExchangeUnbindFunc: func(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+			got = append(got, "exchange "+source+" "+key+" "+destination)
+			return nil
+		},
+		CloseFunc: func() error {
+			return nil
+		},
+	}
+	mock.Conn.ChannelFunc = func() (Channel, error) {
+		return channel, nil
+	}
+
+	require.NoError(t, client.UnbindQueue(QueueBind{Queue: "foo", Exchange: "events", RoutingKey: []string{"a"}}))
+	require.NoError(t, client.UnbindExchange(ExchangeBind{Source: "root", Destination: "events", RoutingKey: []string{"c"}}))
+	assert.Equal(t, []string{"queue events a foo", "exchange root c events"}, got)
+	assert.Len(t, channel.CloseCalls(), 2)
+
+	// the removed bindings are not re-declared on reconnect
+	assert.Equal(t, Topology{
+		QueueBindings:    []QueueBind{{Queue: "foo", Exchange: "events", RoutingKey: []string{"b"}}},
+		ExchangeBindings: []ExchangeBind{},
+	}, client.topology)
+
+	channel.QueueUnbindFunc = func(name string, key string, exchange string, args amqp091.Table) error {
+		return fmt.Errorf("failed")
+	}
+	assert.EqualError(t, client.UnbindQueue(QueueBind{Queue: "foo", Exchange: "events", RoutingKey: []string{"b"}}),
+		`amqpx: unbind queue "foo" exchange "events": failed`)
+	assert.Len(t, client.topology.QueueBindings, 1)
+}
+
+func TestClient_Hooks(t *testing.T) {
+	t.Parallel()
+
+	var connect, disconnect, reconnect atomic.Int32
+	done := make(chan bool, 1)
+	client, mock := prep(t,
+		OnConnect(func() { connect.Add(1) }),
+		OnDisconnect(func(error) { disconnect.Add(1) }),
+		OnReconnect(func(attempt int) {
+			reconnect.Store(int32(attempt))
+			done <- true
+		}))
+	defer client.Close()
+	assert.Equal(t, StateConnected, client.State())
+
+	mock.Conn.Close()
+	select {
+	case <-time.After(defaultTimeout):
+		t.Fatalf("no reconnect")
+	case <-done:
+	}
+
+	assert.Equal(t, int32(1), disconnect.Load())
+	assert.Equal(t, int32(1), reconnect.Load())
+	assert.Eventually(t, func() bool { return client.State() == StateConnected }, defaultTimeout, time.Millisecond)
+	assert.Eventually(t, func() bool { return connect.Load() == 2 }, defaultTimeout, time.Millisecond)
+}
+
+func TestClient_Fatal(t *testing.T) {
+	t.Parallel()
+
+	fatal := make(chan error, 1)
+	client, mock := prep(t, WithLog(NoOpLogger), OnFatal(func(err error) { fatal <- err }))
+	defer client.Close()
+
+	err := fmt.Errorf("dial failed")
+	mock.Dialer.DialFunc = func(_ context.Context) (Connection, error) {
+		return nil, err
+	}
+	mock.Conn.Close()
+
+	select {
+	case <-time.After(defaultTimeout):
+		t.Fatalf("no fatal")
+	case <-client.Done():
+	}
+
+	assert.ErrorIs(t, <-fatal, err)
+	assert.ErrorIs(t, client.Err(), err)
+	assert.Equal(t, StateFailed, client.State())
+}
+
+func TestClient_Close(t *testing.T) {
+	t.Parallel()
+
+	client, _ := prep(t)
+	require.NoError(t, client.Err())
+
+	client.Close()
+	<-client.Done()
+	assert.ErrorIs(t, client.Err(), ErrClosed)
+	assert.Equal(t, StateClosed, client.State())
+}
+
+func TestClient_Blocked(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, WithLog(NoOpLogger))
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	mock.Channel.PublishWithDeferredConfirmWithContextFunc = func(ctx context.Context, exchange string, key string, mandatory bool, immediate bool, msg amqp091.Publishing) (*amqp091.DeferredConfirmation, error) {
+		return nil, nil
+	}
+
+	notify := client.NotifyBlocked(make(chan Blocking, 1))
+	mock.Conn.NotifyBlockedCalls()[0].BlockingCh <- Blocking{Active: true, Reason: "memory"}
+	assert.Equal(t, Blocking{Active: true, Reason: "memory"}, <-notify)
+	assert.True(t, client.IsBlocked())
+
+	b := []byte("hello")
+	t.Run("fail", func(t *testing.T) {
+		pub := NewPublisher[[]byte](client, ExchangeDirect, UseRoutingKey("key"), FailOnBlocked())
+		defer pub.Close()
+
+		assert.ErrorIs(t, pub.Publish(NewPublishing(&b)), ErrConnectionBlocked)
+	})
+
+	t.Run("wait", func(t *testing.T) {
+		pub := NewPublisher[[]byte](client, ExchangeDirect, UseRoutingKey("key"))
+		defer pub.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		err := pub.Publish(NewPublishing(&b), SetContext(ctx))
+		assert.ErrorIs(t, err, ErrConnectionBlocked)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		mock.Conn.NotifyBlockedCalls()[0].BlockingCh <- Blocking{Active: false}
+		assert.Equal(t, Blocking{Active: false}, <-notify)
+		assert.NoError(t, pub.Publish(NewPublishing(&b)))
+	})
+}
+
+func TestClient_BlockedClose(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, WithLog(NoOpLogger))
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	notify := client.NotifyBlocked(make(chan Blocking, 1))
+	mock.Conn.NotifyBlockedCalls()[0].BlockingCh <- Blocking{Active: true, Reason: "memory"}
+	<-notify
+
+	pub := NewPublisher[[]byte](client, ExchangeDirect, UseRoutingKey("key"))
+	defer pub.Close()
+
+	published := make(chan error)
+	go func() {
+		b := []byte("hello")
+		published <- pub.Publish(NewPublishing(&b))
+	}()
+
+	// the blocked publishing is released when the client is closed
+	for client.publishing.len() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	client.Close()
+	err := <-published
+	assert.ErrorIs(t, err, ErrConnectionBlocked)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestClient_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drain", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := prep(t)
+		defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+		ack := &AcknowledgerMock{
+			AckFunc:  func(tag uint64, multiple bool) error { return nil },
+			NackFunc: func(tag uint64, multiple bool, requeue bool) error { return nil },
+		}
+		delivery := make(chan amqp091.Delivery, 3)
+		for i := 1; i <= 3; i++ {
+			delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: uint64(i)}
+		}
+		mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+			return delivery, nil
+		}
+		mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+			close(delivery)
+			return nil
+		}
+
+		started, release := make(chan bool), make(chan bool)
+		_, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+			close(started)
+			<-release
+			assert.NoError(t, ctx.Err())
+			return Ack
+		}), SetPrefetchCount(1))
+		require.NoError(t, err)
+		<-started
+
+		go func() {
+			time.Sleep(time.Millisecond * 10)
+			close(release)
+		}()
+		require.NoError(t, client.Shutdown(context.Background()))
+
+		assert.Equal(t, 1, len(mock.Channel.CancelCalls()))
+		assert.Equal(t, 1, len(ack.AckCalls()))
+		assert.Equal(t, 2, len(ack.NackCalls()))
+		assert.ErrorIs(t, client.Err(), ErrClosed)
+	})
+
+	t.Run("force", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := prep(t)
+		defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+		delivery := make(chan amqp091.Delivery, 1)
+		delivery <- amqp091.Delivery{Acknowledger: &AcknowledgerMock{
+			AckFunc: func(tag uint64, multiple bool) error { return nil },
+		}}
+		mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+			return delivery, nil
+		}
+		mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+			close(delivery)
+			return nil
+		}
+
+		started := make(chan bool)
+		_, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+			close(started)
+			<-ctx.Done()
+			return Ack
+		}))
+		require.NoError(t, err)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		assert.ErrorIs(t, client.Shutdown(ctx), context.DeadlineExceeded)
+		assert.Equal(t, StateClosed, client.State())
+	})
+}
+
 type mock struct {
-	Dialer *dialerMock
+	Dialer *DialerMock
 	Conn   *ConnectionMock
 
 	// returns the same channel for all
@@ -55,6 +465,15 @@ type mock struct {
 
 func channelMock() *ChannelMock {
 	channel := &ChannelMock{
+		ExchangeDeclareFunc: func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+			return nil
+		},
+		ExchangeBindFunc: func(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+			return nil
+		},
+		QueueBindFunc: func(name string, key string, exchange string, noWait bool, args amqp091.Table) error {
+			return nil
+		},
 		QosFunc: func(prefetchCount int, prefetchSize int, global bool) error {
 			return nil
 		},
@@ -92,7 +511,7 @@ func channelMock() *ChannelMock {
 	return channel
 }
 
-func prep(t *testing.T) (*Client, mock) {
+func prep(t *testing.T, opts ...ClientOption) (*Client, mock) {
 	channel := channelMock()
 	conn := &ConnectionMock{
 		IsClosedFunc: func() bool {
@@ -101,12 +520,20 @@ func prep(t *testing.T) (*Client, mock) {
 		NotifyCloseFunc: func(errorCh chan *amqp091.Error) chan *amqp091.Error {
 			return errorCh
 		},
+		NotifyBlockedFunc: func(blockingCh chan amqp091.Blocking) chan amqp091.Blocking {
+			return blockingCh
+		},
 		ChannelFunc: func() (Channel, error) {
 			return channel, nil
 		},
 	}
 
+	m := sync.Mutex{}
+	closedBlocked := 0
 	conn.CloseFunc = func() error {
+		m.Lock()
+		defer m.Unlock()
+
 		defer channel.Close()
 		for _, v := range conn.NotifyCloseCalls() {
 			select {
@@ -115,11 +542,16 @@ func prep(t *testing.T) (*Client, mock) {
 				close(v.ErrorCh)
 			}
 		}
+
+		for _, v := range conn.NotifyBlockedCalls()[closedBlocked:] {
+			close(v.BlockingCh)
+		}
+		closedBlocked = len(conn.NotifyBlockedCalls())
 		return nil
 	}
 
 	mock := mock{
-		Dialer: &dialerMock{
+		Dialer: &DialerMock{
 			DialFunc: func(_ context.Context) (Connection, error) {
 				return conn, nil
 			},
@@ -128,10 +560,10 @@ func prep(t *testing.T) (*Client, mock) {
 		Channel: channel,
 	}
 
-	client, err := Connect(setDialer(mock.Dialer),
-		WithLog(func(format string, v ...any) { t.Fatalf(format, v...) }),
+	client, err := Connect(append([]ClientOption{WithDialer(mock.Dialer),
+		WithLogHandler(testLogHandler{t: t}),
 		UseUnmarshaler(testUnmarshaler),
-		UseMarshaler(defaultBytesMarshaler))
+		UseMarshaler(defaultBytesMarshaler)}, opts...)...)
 	require.NoError(t, err)
 	return client, mock
 }
@@ -27,9 +27,15 @@ var _ Connection = &ConnectionMock{}
 //			IsClosedFunc: func() bool {
 //				panic("mock out the IsClosed method")
 //			},
+//			NotifyBlockedFunc: func(blockingCh chan amqp091.Blocking) chan amqp091.Blocking {
+//				panic("mock out the NotifyBlocked method")
+//			},
 //			NotifyCloseFunc: func(errorCh chan *amqp091.Error) chan *amqp091.Error {
 //				panic("mock out the NotifyClose method")
 //			},
+//			UpdateSecretFunc: func(newSecret string, reason string) error {
+//				panic("mock out the UpdateSecret method")
+//			},
 //		}
 //
 //		// use mockedConnection in code that requires Connection
@@ -46,9 +52,15 @@ type ConnectionMock struct {
 	// IsClosedFunc mocks the IsClosed method.
 	IsClosedFunc func() bool
 
+	// NotifyBlockedFunc mocks the NotifyBlocked method.
+	NotifyBlockedFunc func(blockingCh chan amqp091.Blocking) chan amqp091.Blocking
+
 	// NotifyCloseFunc mocks the NotifyClose method.
 	NotifyCloseFunc func(errorCh chan *amqp091.Error) chan *amqp091.Error
 
+	// UpdateSecretFunc mocks the UpdateSecret method.
+	UpdateSecretFunc func(newSecret string, reason string) error
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Channel holds details about calls to the Channel method.
@@ -60,16 +72,30 @@ type ConnectionMock struct {
 		// IsClosed holds details about calls to the IsClosed method.
 		IsClosed []struct {
 		}
+		// NotifyBlocked holds details about calls to the NotifyBlocked method.
+		NotifyBlocked []struct {
+			// BlockingCh is the blockingCh argument value.
+			BlockingCh chan amqp091.Blocking
+		}
 		// NotifyClose holds details about calls to the NotifyClose method.
 		NotifyClose []struct {
 			// ErrorCh is the errorCh argument value.
 			ErrorCh chan *amqp091.Error
 		}
+		// UpdateSecret holds details about calls to the UpdateSecret method.
+		UpdateSecret []struct {
+			// NewSecret is the newSecret argument value.
+			NewSecret string
+			// Reason is the reason argument value.
+			Reason string
+		}
 	}
-	lockChannel     sync.RWMutex
-	lockClose       sync.RWMutex
-	lockIsClosed    sync.RWMutex
-	lockNotifyClose sync.RWMutex
+	lockChannel       sync.RWMutex
+	lockClose         sync.RWMutex
+	lockIsClosed      sync.RWMutex
+	lockNotifyBlocked sync.RWMutex
+	lockNotifyClose   sync.RWMutex
+	lockUpdateSecret  sync.RWMutex
 }
 
 // Channel calls ChannelFunc.
@@ -153,6 +179,38 @@ func (mock *ConnectionMock) IsClosedCalls() []struct {
 	return calls
 }
 
+// NotifyBlocked calls NotifyBlockedFunc.
+func (mock *ConnectionMock) NotifyBlocked(blockingCh chan amqp091.Blocking) chan amqp091.Blocking {
+	if mock.NotifyBlockedFunc == nil {
+		panic("ConnectionMock.NotifyBlockedFunc: method is nil but Connection.NotifyBlocked was just called")
+	}
+	callInfo := struct {
+		BlockingCh chan amqp091.Blocking
+	}{
+		BlockingCh: blockingCh,
+	}
+	mock.lockNotifyBlocked.Lock()
+	mock.calls.NotifyBlocked = append(mock.calls.NotifyBlocked, callInfo)
+	mock.lockNotifyBlocked.Unlock()
+	return mock.NotifyBlockedFunc(blockingCh)
+}
+
+// NotifyBlockedCalls gets all the calls that were made to NotifyBlocked.
+// Check the length with:
+//
+//	len(mockedConnection.NotifyBlockedCalls())
+func (mock *ConnectionMock) NotifyBlockedCalls() []struct {
+	BlockingCh chan amqp091.Blocking
+} {
+	var calls []struct {
+		BlockingCh chan amqp091.Blocking
+	}
+	mock.lockNotifyBlocked.RLock()
+	calls = mock.calls.NotifyBlocked
+	mock.lockNotifyBlocked.RUnlock()
+	return calls
+}
+
 // NotifyClose calls NotifyCloseFunc.
 func (mock *ConnectionMock) NotifyClose(errorCh chan *amqp091.Error) chan *amqp091.Error {
 	if mock.NotifyCloseFunc == nil {
@@ -184,3 +242,39 @@ func (mock *ConnectionMock) NotifyCloseCalls() []struct {
 	mock.lockNotifyClose.RUnlock()
 	return calls
 }
+
+// UpdateSecret calls UpdateSecretFunc.
+func (mock *ConnectionMock) UpdateSecret(newSecret string, reason string) error {
+	if mock.UpdateSecretFunc == nil {
+		panic("ConnectionMock.UpdateSecretFunc: method is nil but Connection.UpdateSecret was just called")
+	}
+	callInfo := struct {
+		NewSecret string
+		Reason    string
+	}{
+		NewSecret: newSecret,
+		Reason:    reason,
+	}
+	mock.lockUpdateSecret.Lock()
+	mock.calls.UpdateSecret = append(mock.calls.UpdateSecret, callInfo)
+	mock.lockUpdateSecret.Unlock()
+	return mock.UpdateSecretFunc(newSecret, reason)
+}
+
+// UpdateSecretCalls gets all the calls that were made to UpdateSecret.
+// Check the length with:
+//
+//	len(mockedConnection.UpdateSecretCalls())
+func (mock *ConnectionMock) UpdateSecretCalls() []struct {
+	NewSecret string
+	Reason    string
+} {
+	var calls []struct {
+		NewSecret string
+		Reason    string
+	}
+	mock.lockUpdateSecret.RLock()
+	calls = mock.calls.UpdateSecret
+	mock.lockUpdateSecret.RUnlock()
+	return calls
+}
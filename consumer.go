@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rabbitmq/amqp091-go"
@@ -25,7 +27,7 @@ type ConsumeFunc func(ctx context.Context, req *DeliveryRequest) Action
 
 type HandlerValue interface {
 	serve(ctx context.Context, req *DeliveryRequest) Action
-	init(map[string]Unmarshaler)
+	init(map[string]Unmarshaler, ErrorPolicy)
 }
 
 // handleValue represents consume message unmarshales bytes into
@@ -33,6 +35,7 @@ type HandlerValue interface {
 type handleValue[T any] struct {
 	fn          func(context.Context, *Delivery[T]) Action
 	unmarshaler map[string]Unmarshaler
+	errorPolicy ErrorPolicy
 	bytesMsg    bool
 }
 
@@ -45,29 +48,173 @@ func D[T any](fn func(ctx context.Context, d *Delivery[T]) Action) *handleValue[
 	return &handleValue[T]{fn: fn}
 }
 
-func (v *handleValue[T]) init(m map[string]Unmarshaler) {
+func (v *handleValue[T]) init(m map[string]Unmarshaler, p ErrorPolicy) {
 	v.unmarshaler = m
+	v.errorPolicy = p
 }
 
 func (v *handleValue[T]) serve(ctx context.Context, req *DeliveryRequest) Action {
-	if v.bytesMsg {
-		return v.fn(ctx, &Delivery[T]{Msg: any(&req.in.Body).(*T), Req: req})
+	d, ok := decodeDelivery[T](req, v.unmarshaler, v.bytesMsg)
+	if !ok {
+		return Reject
+	}
+	return v.fn(ctx, d)
+}
+
+// decodeDelivery unmarshales the message, it returns false if the delivery must be rejected.
+func decodeDelivery[T any](req *DeliveryRequest, unmarshaler map[string]Unmarshaler, bytesMsg bool) (*Delivery[T], bool) {
+	if bytesMsg {
+		return &Delivery[T]{Msg: any(&req.in.Body).(*T), Req: req}, true
 	}
 
-	u, ok := v.unmarshaler[req.in.ContentType]
+	u, ok := unmarshaler[req.in.ContentType]
 	if !ok {
-		req.log("[ERROR] %s: %s", req.info(), errUnmarshalerNotFound)
-		return Reject
+		req.err = errUnmarshalerNotFound
+		req.Logger().Error("reject delivery", LogKeyError, req.err)
+		return nil, false
 	}
 
 	value := new(T)
 	if err := u.Unmarshal(req.in.Body, value); err != nil {
-		req.log("[ERROR] %s: %s", req.info(), fmt.Errorf("has an error trying to unmarshal: %w", err))
-		return Reject
+		req.err = fmt.Errorf("has an error trying to unmarshal: %w", err)
+		req.Logger().Error("reject delivery", LogKeyError, req.err)
+		return nil, false
 	}
 
 	req.in.Body = nil
-	return v.fn(ctx, newDelivery(value, req))
+	return newDelivery(value, req), true
+}
+
+// A Consumer represents the handle of the started consumer.
+type Consumer struct {
+	c      *consumer
+	remove func()
+}
+
+// Queue returns the queue name.
+func (c *Consumer) Queue() string {
+	return c.c.queue
+}
+
+// Tag returns the consumer tag.
+func (c *Consumer) Tag() string {
+	return c.c.tag
+}
+
+// Pause stops receiving the deliveries, the channel is kept open.
+// The consumer is cancelled on the server, the prefetched deliveries are requeued
+// and the in-flight handlers are not interrupted. It returns immediately.
+func (c *Consumer) Pause() {
+	c.c.setPaused(true)
+}
+
+// Resume starts receiving the deliveries after Pause.
+func (c *Consumer) Resume() {
+	c.c.setPaused(false)
+}
+
+// Cancel stops the consumer and closes its channel.
+// It cancels the consumer on the server, requeues the prefetched deliveries
+// and waits the in-flight handlers with their original contexts.
+// If ctx is done before, the contexts of the handlers are cancelled and ctx error is returned.
+func (c *Consumer) Cancel(ctx context.Context) error {
+	c.c.cancelStop()
+	err := waitContext(ctx, func() { <-c.c.stopped })
+	if err == nil {
+		err = c.c.inflight.wait(ctx)
+	}
+	c.remove()
+
+	if err != nil {
+		c.c.delivery.cancel()
+	}
+	<-c.c.stopped
+	c.c.close()
+	return err
+}
+
+// Stats returns the statistics of the consumer.
+func (c *Consumer) Stats() ConsumerStats {
+	stats := c.c.stats.load()
+	stats.InFlight = c.c.inflight.len()
+	return stats
+}
+
+// A ConsumerStats represents the statistics of the consumer.
+type ConsumerStats struct {
+	// InFlight is the number of the deliveries which are being handled.
+	InFlight int
+	Acked    uint64
+	Nacked   uint64
+	Rejected uint64
+	Retried  uint64
+	// TimedOut is the number of the handlers which exceeded the handler timeout.
+	TimedOut uint64
+	// Abandoned is the number of the timed out handlers which are still running,
+	// they are not waited by Cancel and Shutdown.
+	Abandoned int
+	// SlowHandlers is the number of the handlers which exceeded the slow handler threshold.
+	SlowHandlers uint64
+	// HandlerLatency is the average duration of the handler.
+	HandlerLatency    time.Duration
+	MaxHandlerLatency time.Duration
+}
+
+type consumerStats struct {
+	acked     atomic.Uint64
+	nacked    atomic.Uint64
+	rejected  atomic.Uint64
+	retried   atomic.Uint64
+	timedOut  atomic.Uint64
+	abandoned atomic.Int64
+	slow      atomic.Uint64
+	handled   atomic.Uint64
+	latency   atomic.Int64
+	max       atomic.Int64
+}
+
+func (s *consumerStats) handle(d time.Duration) {
+	s.handled.Add(1)
+	s.latency.Add(int64(d))
+	for {
+		max := s.max.Load()
+		if int64(d) <= max || s.max.CompareAndSwap(max, int64(d)) {
+			return
+		}
+	}
+}
+
+func (s *consumerStats) status(status Action) {
+	switch status {
+	case Ack:
+		s.acked.Add(1)
+
+	case Nack:
+		s.nacked.Add(1)
+
+	case Reject:
+		s.rejected.Add(1)
+
+	case Retry:
+		s.retried.Add(1)
+	}
+}
+
+func (s *consumerStats) load() ConsumerStats {
+	stats := ConsumerStats{
+		Acked:             s.acked.Load(),
+		Nacked:            s.nacked.Load(),
+		Rejected:          s.rejected.Load(),
+		Retried:           s.retried.Load(),
+		TimedOut:          s.timedOut.Load(),
+		Abandoned:         int(s.abandoned.Load()),
+		SlowHandlers:      s.slow.Load(),
+		MaxHandlerLatency: time.Duration(s.max.Load()),
+	}
+	if n := s.handled.Load(); n != 0 {
+		stats.HandlerLatency = time.Duration(s.latency.Load() / int64(n))
+	}
+	return stats
 }
 
 type consumer struct {
@@ -80,17 +227,43 @@ type consumer struct {
 	tag              string
 	opts             channelOptions
 
-	limit *semaphore.Weighted
-	wg    *sync.WaitGroup
-	fn    ConsumeFunc
-
-	log  LogFunc
-	done context.Context
+	limit   *semaphore.Weighted
+	wg      *sync.WaitGroup
+	fn      ConsumeFunc
+	backoff Backoff
+	stream  *streamCursor
+	// batch collects the deliveries of the batch handler
+	batch *batch
+	// acks coalesces the acknowledgements of the channel
+	acks *ackBatcher
+	// retry publishes the deliveries which are handled with the Retry action
+	retry *retrier
+	// watchdog limits the duration of the handlers
+	watchdog *watchdog
+
+	// onActive is called from the serve goroutine in single active consumer mode
+	onActive func(active bool)
+	active   bool
+
+	paused   atomic.Bool
+	pause    chan struct{}
+	inflight *inflight
+	stats    consumerStats
+
+	log     *slog.Logger
+	limiter logLimiter
+	ready   <-chan struct{}
+	// connected returns the channel which is closed when the connection is opened again
+	connected  func() <-chan struct{}
+	done       context.Context
+	stop       context.Context
+	cancelStop context.CancelFunc
+	stopped    chan struct{}
 }
 
 func (c *consumer) initChannel() error {
 	conn := c.conn()
-	if conn.IsClosed() {
+	if conn == nil || conn.IsClosed() {
 		return errConnClosed
 	}
 
@@ -123,6 +296,32 @@ func (c *consumer) initChannel() error {
 		}
 	}
 
+	if c.retry != nil {
+		if err := c.retry.declare(channel); err != nil {
+			return err
+		}
+	}
+
+	parkingLot := c.opts.poison != nil && c.opts.poison.ParkingLot != ""
+	if parkingLot {
+		if _, err := channel.QueueDeclare(c.opts.poison.ParkingLot, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("declare parking lot: %w", err)
+		}
+	}
+
+	// the published copy is confirmed before the delivery is acknowledged
+	if c.retry != nil || parkingLot {
+		if err := channel.Confirm(false); err != nil {
+			return fmt.Errorf("confirm: %w", err)
+		}
+	}
+
+	for _, v := range c.opts.exchangeBind {
+		if err := bindExchange(channel, v); err != nil {
+			return err
+		}
+	}
+
 	if c.opts.queueBind != nil {
 		for _, k := range c.opts.queueBind.RoutingKey {
 			if err := channel.QueueBind(c.queue, k,
@@ -145,10 +344,16 @@ func (c *consumer) initChannel() error {
 		c.channel.Close()
 	}
 	c.channel = channel
+	if c.opts.ackSize != 0 {
+		c.acks = newAckBatcher(c.opts.ackSize, c.opts.ackInterval, c.stop, c.log)
+	}
 
-	delivery, err := c.channel.Consume(c.queue, c.tag, c.opts.autoAck, c.opts.exclusive, false, false, nil)
-	if err != nil {
-		return fmt.Errorf("consume: %w", err)
+	// the paused consumer keeps the channel without consuming
+	var delivery <-chan amqp091.Delivery
+	if !c.paused.Load() {
+		if delivery, err = c.consume(); err != nil {
+			return err
+		}
 	}
 
 	c.notifyAMQPClose = c.channel.NotifyClose(make(chan *amqp091.Error, 1))
@@ -157,14 +362,57 @@ func (c *consumer) initChannel() error {
 	return nil
 }
 
+func (c *consumer) consume() (<-chan amqp091.Delivery, error) {
+	delivery, err := c.channel.Consume(c.queue, c.tag, c.opts.autoAck, c.opts.exclusive, c.opts.noLocal, false, c.consumeArgs())
+	if err != nil {
+		return nil, fmt.Errorf("consume: %w", err)
+	}
+	return delivery, nil
+}
+
+func (c *consumer) consumeArgs() amqp091.Table {
+	if c.stream == nil {
+		return c.opts.args
+	}
+
+	args := amqp091.Table{ArgStreamOffset: c.stream.offset().value}
+	for k, v := range c.opts.args {
+		if k != ArgStreamOffset {
+			args[k] = v
+		}
+	}
+	return args
+}
+
 func (c *consumer) serve() {
-	defer c.close()
+	defer close(c.stopped)
+	defer c.setActive(false)
+
+	// the channel is opened once the connection is opened in lazy mode
+	if c.channel == nil {
+		if exit := c.makeConnect(); exit {
+			c.close()
+			return
+		}
+	}
 
 	for {
 		select {
-		case <-c.done.Done():
+		case <-c.stop.Done():
+			c.shutdown()
 			return
 
+		case <-c.pause:
+			c.applyPause()
+			continue
+
+		case <-c.batch.expired():
+			if ok := c.flushBatch(c.delivery.ctx); !ok {
+				c.shutdown()
+				return
+			}
+			continue
+
 		case <-c.notifyAMQPClose:
 		case <-c.notifyAMQPCancel:
 
@@ -173,58 +421,351 @@ func (c *consumer) serve() {
 				break
 			}
 
+			c.setActive(true)
+			c.acks.wrap(&d)
+			c.stream.begin(&d)
+			if c.batch != nil {
+				if full := c.batch.add(&d); full && !c.flushBatch(c.delivery.ctx) {
+					c.shutdown()
+					return
+				}
+				continue
+			}
+
 			c.wg.Add(1)
-			if err := c.limit.Acquire(c.done, 1); err != nil {
+			c.inflight.add()
+			if err := c.limit.Acquire(c.stop, 1); err != nil {
+				c.wg.Done()
+				c.inflight.done()
+				c.requeue(&d)
+				c.shutdown()
 				return
 			}
 
-			go c.handleDelivery(c.delivery.ctx, &d)
+			go c.handleDelivery(c.delivery.ctx, c.channel, &d)
 			continue
 		}
 
+		// the broker activates another consumer when the channel is closed or cancelled
+		c.setActive(false)
 		if exit := c.makeConnect(); exit {
+			c.close()
 			return
 		}
 	}
 }
 
+// setActive reports the activity of the single active consumer.
+// The consumer becomes active on the first delivery and goes standby when the channel is lost.
+func (c *consumer) setActive(active bool) {
+	if c.onActive == nil || c.active == active {
+		return
+	}
+
+	c.active = active
+	c.log.Info("single active consumer", "active", active)
+	c.onActive(active)
+}
+
+// setPaused requests the serve goroutine to pause or resume consuming.
+func (c *consumer) setPaused(paused bool) {
+	c.paused.Store(paused)
+	select {
+	case c.pause <- struct{}{}:
+	default:
+	}
+}
+
+// applyPause cancels consuming of the channel or consumes again.
+// The in-flight handlers keep their contexts and the channel.
+func (c *consumer) applyPause() {
+	paused := c.paused.Load()
+	if paused == (c.delivery.channel == nil) {
+		return
+	}
+
+	if paused {
+		c.cancel()
+		c.delivery.channel = nil
+		c.setActive(false)
+		c.log.Info("consumer is paused")
+		return
+	}
+
+	delivery, err := c.consume()
+	if err != nil {
+		// the channel is reopened by the reconnect
+		c.log.Error("resume", LogKeyError, err)
+		c.channel.Close()
+		return
+	}
+	c.delivery.channel = delivery
+	c.log.Info("consumer is resumed")
+}
+
+// flushBatch starts handling of the pending deliveries with ctx, it returns false if the consumer is stopped.
+func (c *consumer) flushBatch(ctx context.Context) bool {
+	pending := c.batch.take()
+	if len(pending) == 0 {
+		return true
+	}
+
+	c.wg.Add(len(pending))
+	for range pending {
+		c.inflight.add()
+	}
+
+	// the previous batch is waited
+	if err := c.limit.Acquire(c.stop, 1); err != nil {
+		for _, d := range pending {
+			c.wg.Done()
+			c.inflight.done()
+			c.requeue(d)
+		}
+		return false
+	}
+
+	go c.handleBatch(ctx, c.channel, pending)
+	return true
+}
+
+// shutdown stops the consumer.
+// The consumer is cancelled and the in-flight handlers keep the channel
+// when the client is shutting down gracefully, otherwise the channel is closed.
+func (c *consumer) shutdown() {
+	if c.done.Err() != nil || c.channel == nil {
+		c.close()
+		return
+	}
+
+	// the pending batch has not been started
+	for _, d := range c.batch.take() {
+		c.requeue(d)
+	}
+
+	// the paused consumer is already cancelled
+	if c.delivery.channel != nil {
+		c.cancel()
+	}
+
+	// the handlers finished after the stop send their acks immediately
+	c.acks.flush()
+}
+
+// cancel cancels consuming and requeues the prefetched deliveries.
+func (c *consumer) cancel() {
+	if err := c.channel.Cancel(c.tag, false); err != nil {
+		c.log.Error("cancel", LogKeyError, err)
+		return
+	}
+
+	// the prefetched deliveries are flushed until the delivery channel closes
+	for d := range c.delivery.channel {
+		c.acks.wrap(&d)
+		c.stream.begin(&d)
+		c.requeue(&d)
+	}
+}
+
+// requeue returns the delivery which has not been started to the queue.
+// The delivery is already acknowledged by the server in auto-ack mode, so it is handled
+// with the context of the client as the channel can be lost.
+func (c *consumer) requeue(d *amqp091.Delivery) {
+	if c.opts.autoAck {
+		c.wg.Add(1)
+		c.inflight.add()
+		_ = c.limit.Acquire(context.Background(), 1)
+		go c.handleDelivery(c.done, c.channel, d)
+		return
+	}
+
+	c.stream.done(d, false)
+	if err := d.Nack(false, true); err != nil {
+		c.log.Error("requeue", LogKeyDeliveryTag, d.DeliveryTag, LogKeyError, err)
+	}
+}
+
 func (c *consumer) makeConnect() (exit bool) {
 	c.delivery.cancel()
 
-	for {
-		var err error
-		if err = c.initChannel(); err == nil {
+	// the pending deliveries are already acknowledged by the server in auto-ack mode,
+	// they are handled with the context of the client as the channel is lost
+	if c.opts.autoAck {
+		c.flushBatch(c.done)
+	}
+
+	// the pending deliveries of the lost channel are redelivered by the server
+	for _, d := range c.batch.take() {
+		c.stream.done(d, false)
+	}
+
+	select {
+	case <-c.ready:
+	case <-c.stop.Done():
+		return true
+	}
+
+	for attempt := 1; ; attempt++ {
+		connected := c.connected()
+		err := c.initChannel()
+		if err == nil {
+			c.limiter.reset()
 			return false
 		}
 
-		if !errors.Is(err, errConnClosed) {
-			c.log("[ERROR] queue %q consumer-tag %q: %s", c.queue, c.tag, err)
+		// the client reconnects the connection, so the attempts are not spent
+		if errors.Is(err, errConnClosed) {
+			select {
+			case <-c.stop.Done():
+				return true
+
+			case <-connected:
+			}
+			attempt--
+			continue
+		}
+
+		logReconnectError(c.log, &c.limiter, "init channel", attempt, err)
+		delay, ok := c.backoff.Next(attempt, err)
+		if !ok {
+			c.log.Error("init channel", LogKeyAttempt, attempt, LogKeyError, errMaxAttempts)
+			return true
 		}
 
 		select {
-		case <-c.done.Done():
+		case <-c.stop.Done():
 			return true
 
-		case <-time.After(defaultReconnectDelay):
+		case <-time.After(delay):
 		}
 	}
 }
 
-func (c *consumer) handleDelivery(ctx context.Context, d *amqp091.Delivery) {
+func (c *consumer) handleDelivery(ctx context.Context, channel Channel, d *amqp091.Delivery) {
 	defer c.wg.Done()
+	defer c.inflight.done()
 	defer c.limit.Release(1)
 
+	// the handler can change the delivery, the original is retried
+	orig := *d
 	delivery := newDeliveryRequest(d, c.log)
-	if status := c.fn(ctx, delivery); !c.opts.autoAck {
-		if err := delivery.setStatus(status); err != nil {
-			c.log("[ERROR] queue %q consumer-tag %q: %s", c.queue, c.tag, err)
+	status, poisoned := c.checkPoison(ctx, channel, &orig, delivery)
+	if !poisoned {
+		start := time.Now()
+		status = watch(c.watchdog, ctx, delivery.Logger(), func(ctx context.Context) Action {
+			return c.fn(ctx, delivery)
+		}, func() Action {
+			return c.watchdog.action
+		})
+		c.stats.handle(time.Since(start))
+	}
+
+	c.stream.done(&orig, true)
+
+	// the delivery is acknowledged by the server in auto-ack mode
+	if c.opts.autoAck {
+		c.stats.status(Ack)
+		return
+	}
+
+	action := status
+	if status == Retry {
+		if action = c.retryDelivery(ctx, channel, &orig, delivery); action != Ack {
+			status = action
 		}
 	}
+
+	if err := delivery.setStatus(action); err != nil {
+		delivery.Logger().Error("set status", "status", action, LogKeyError, err)
+		return
+	}
+	c.stats.status(status)
+}
+
+func (c *consumer) handleBatch(ctx context.Context, channel Channel, pending []*amqp091.Delivery) {
+	defer func() {
+		for range pending {
+			c.inflight.done()
+			c.wg.Done()
+		}
+	}()
+	defer c.limit.Release(1)
+
+	// the handler can change the deliveries, the originals are retried
+	origs := make([]amqp091.Delivery, len(pending))
+	reqs := make([]*DeliveryRequest, len(pending))
+	for i, d := range pending {
+		origs[i] = *d
+		reqs[i] = newDeliveryRequest(d, c.log)
+	}
+
+	actions := make([]Action, len(reqs))
+	handled := make([]*DeliveryRequest, 0, len(reqs))
+	index := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		if action, poisoned := c.checkPoison(ctx, channel, &origs[i], req); poisoned {
+			actions[i] = action
+			continue
+		}
+
+		handled = append(handled, req)
+		index = append(index, i)
+	}
+
+	if len(handled) != 0 {
+		start := time.Now()
+		result := watch(c.watchdog, ctx, c.log, func(ctx context.Context) []Action {
+			return c.batch.fn(ctx, handled)
+		}, func() []Action {
+			actions := make([]Action, len(handled))
+			for i := range actions {
+				actions[i] = c.watchdog.action
+			}
+			return actions
+		})
+		c.stats.handle(time.Since(start))
+		for j, i := range index {
+			actions[i] = result[j]
+		}
+	}
+
+	for i := range origs {
+		c.stream.done(&origs[i], true)
+	}
+
+	// the deliveries are acknowledged by the server in auto-ack mode
+	if c.opts.autoAck {
+		for range reqs {
+			c.stats.status(Ack)
+		}
+		return
+	}
+
+	statuses := make([]Action, len(actions))
+	for i, status := range actions {
+		if status == Retry {
+			if actions[i] = c.retryDelivery(ctx, channel, &origs[i], reqs[i]); actions[i] != Ack {
+				status = actions[i]
+			}
+		}
+		statuses[i] = status
+	}
+
+	for i, err := range settleBatch(reqs, actions) {
+		if err != nil {
+			reqs[i].Logger().Error("set status", "status", actions[i], LogKeyError, err)
+			continue
+		}
+		c.stats.status(statuses[i])
+	}
 }
 
 func (c *consumer) close() {
 	c.delivery.cancel()
-	c.channel.Close()
+	c.acks.flush()
+	if c.channel != nil {
+		c.channel.Close()
+	}
 }
 
 type channelDelivery struct {
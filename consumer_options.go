@@ -1,11 +1,22 @@
 package amqpx
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"sync/atomic"
+	"time"
 )
 
 var defaultLimitConcurrency = runtime.GOMAXPROCS(0)
 
+var consumerSeq atomic.Uint64
+
+func uniqueConsumerTag() string {
+	return fmt.Sprintf("ctag-%s-%d", filepath.Base(os.Args[0]), consumerSeq.Add(1))
+}
+
 // ConsumerOption is used to configure a consumer.
 type ConsumerOption func(*consumerOptions)
 
@@ -15,6 +26,15 @@ type consumerOptions struct {
 	concurrency int
 	interceptor []ConsumeInterceptor
 	unmarshaler map[string]Unmarshaler
+	backoff     Backoff
+	onActive    func(active bool)
+	errorPolicy ErrorPolicy
+	panicAction Action
+	noRecover   bool
+
+	handlerTimeout time.Duration
+	timeoutAction  Action
+	slowThreshold  time.Duration
 }
 
 type channelOptions struct {
@@ -24,6 +44,14 @@ type channelOptions struct {
 	queueDeclare    *QueueDeclare
 	exchangeDeclare *ExchangeDeclare
 	queueBind       *QueueBind
+	exchangeBind    []ExchangeBind
+	streamOffset    *StreamOffset
+	noLocal         bool
+	args            Table
+	ackSize         int
+	ackInterval     time.Duration
+	retry           *RetryPolicy
+	poison          *PoisonPolicy
 }
 
 func (c *consumerOptions) validate(fn HandlerValue) error {
@@ -31,8 +59,77 @@ func (c *consumerOptions) validate(fn HandlerValue) error {
 		return errFuncNil
 	}
 
-	if _, ok := fn.(*handleValue[[]byte]); !ok && len(c.unmarshaler) == 0 {
-		return errUnmarshalerNotFound
+	switch fn.(type) {
+	case *handleValue[[]byte], *batchValue[[]byte]:
+	default:
+		if len(c.unmarshaler) == 0 {
+			return errUnmarshalerNotFound
+		}
+	}
+
+	if c.channel.ackSize != 0 || c.channel.ackInterval != 0 {
+		if c.channel.ackSize <= 0 || c.channel.ackInterval <= 0 {
+			return errAckCoalescingLimits
+		}
+		if c.channel.autoAck {
+			return errAckCoalescingAutoAck
+		}
+		if _, ok := fn.(batchHandler); ok {
+			return errAckCoalescingBatch
+		}
+	}
+
+	if c.channel.retry != nil {
+		if err := c.channel.retry.validate(); err != nil {
+			return err
+		}
+		if c.channel.autoAck {
+			return errRetryAutoAck
+		}
+	}
+
+	if c.channel.poison != nil {
+		if c.channel.poison.MaxRedeliveries < 0 {
+			return errPoisonPolicy
+		}
+		if c.channel.autoAck {
+			return errPoisonAutoAck
+		}
+	}
+
+	b, isBatch := fn.(batchHandler)
+	if isBatch {
+		size, wait := b.limits()
+		if size <= 0 || wait <= 0 {
+			return errBatchLimits
+		}
+
+		// the batch is filled by the prefetched deliveries
+		if !c.channel.autoAck {
+			if c.channel.prefetchCount == 0 {
+				c.channel.prefetchCount = size
+			}
+			if c.channel.prefetchCount < size {
+				return errBatchPrefetch
+			}
+		}
+	}
+
+	if c.channel.streamOffset != nil {
+		if c.channel.autoAck {
+			return errStreamAutoAck
+		}
+		if c.channel.prefetchCount == 0 {
+			return errStreamPrefetch
+		}
+	}
+
+	if c.onActive != nil && c.channel.queueDeclare != nil {
+		args := Table{ArgSingleActiveConsumer: true}
+		for k, v := range c.channel.queueDeclare.Args {
+			args[k] = v
+		}
+		c.channel.queueDeclare.Args = args
 	}
 
 	if !c.channel.autoAck {
@@ -42,6 +139,24 @@ func (c *consumerOptions) validate(fn HandlerValue) error {
 	if c.concurrency == 0 {
 		c.concurrency = defaultLimitConcurrency
 	}
+
+	// the batches are handled one at a time
+	if isBatch {
+		c.concurrency = 1
+	}
+
+	if c.backoff == nil {
+		c.backoff = defaultBackoff
+	}
+
+	if c.errorPolicy == nil {
+		c.errorPolicy = DefaultErrorPolicy
+	}
+
+	// the tag is required to cancel the consumer
+	if c.tag == "" {
+		c.tag = uniqueConsumerTag()
+	}
 	return nil
 }
 
@@ -134,3 +249,179 @@ func BindQueue(q QueueBind) ConsumerOption {
 		o.channel.queueBind = &q
 	}
 }
+
+// BindExchange sets exchange to exchange bind, it can be used several times.
+// The destination exchange is bound before the queue bind.
+func BindExchange(e ExchangeBind) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.channel.exchangeBind = append(o.channel.exchangeBind, e)
+	}
+}
+
+// SetStreamOffset sets starting point of the stream queue consumer.
+// The stream consumer requires prefetch count and does not support auto-ack mode.
+// On reconnect the consumer resumes after the last processed offset.
+func SetStreamOffset(offset StreamOffset) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.channel.streamOffset = &offset
+	}
+}
+
+// ArgConsumerPriority and ArgConsumerTimeout are the consume arguments.
+const (
+	ArgConsumerPriority = "x-priority"
+	ArgConsumerTimeout  = "x-consumer-timeout"
+)
+
+// SetNoLocal sets no local.
+// The default is false.
+//
+// When noLocal is true, the server will not deliver publishing sent from the same
+// connection to this consumer. It is not supported by RabbitMQ.
+func SetNoLocal(b bool) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.channel.noLocal = b
+	}
+}
+
+// SetConsumerPriority sets priority of the consumer.
+// The default is 0, the consumers with higher priority receive the messages first
+// while they are able to, the consumers with lower priority receive them when the higher are blocked.
+func SetConsumerPriority(priority int) ConsumerOption {
+	return SetConsumerArgs(Table{ArgConsumerPriority: priority})
+}
+
+// SetConsumerTimeout sets delivery acknowledgement timeout of the consumer, it requires RabbitMQ 3.12+.
+// The default is the broker setting (30m), the channel is closed when the delivery is not acknowledged in time.
+// The older brokers ignore the argument, the timeout of all consumers of the queue
+// is set by ArgConsumerTimeout in the queue arguments or by the policy.
+func SetConsumerTimeout(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		if d > 0 {
+			SetConsumerArgs(Table{ArgConsumerTimeout: d.Milliseconds()})(o)
+		}
+	}
+}
+
+// SetConsumerArgs sets additional consume arguments, it can be used several times.
+func SetConsumerArgs(args Table) ConsumerOption {
+	return func(o *consumerOptions) {
+		if len(args) == 0 {
+			return
+		}
+
+		if o.channel.args == nil {
+			o.channel.args = make(Table, len(args))
+		}
+		for k, v := range args {
+			o.channel.args[k] = v
+		}
+	}
+}
+
+// SetSingleActiveConsumer notifies about activity of the consumer of the queue with x-single-active-consumer,
+// so only one consumer of the queue receives the messages at a time and the others are standby.
+// The argument is added to DeclareQueue when it is set, otherwise the queue must be already declared with it.
+//
+// fn is called with true when the consumer becomes active on the first delivery
+// and with false when it goes standby because the channel is closed or the consumer is cancelled.
+// fn is called from the consumer goroutine, so it must not block.
+func SetSingleActiveConsumer(fn func(active bool)) ConsumerOption {
+	return func(o *consumerOptions) {
+		if fn == nil {
+			fn = func(bool) {}
+		}
+		o.onActive = fn
+	}
+}
+
+// SetConsumerBackoff sets reconnect backoff policy of the consumer channel.
+// The default is the client policy.
+func SetConsumerBackoff(b Backoff) ConsumerOption {
+	return func(o *consumerOptions) {
+		if b != nil {
+			o.backoff = b
+		}
+	}
+}
+
+// SetAckCoalescing coalesces the acknowledgements of the channel.
+// The acks are sent with the multiple flag when size acks are pending or interval is passed,
+// the nacks and rejects are sent immediately. The pending acks are sent when the consumer is stopping.
+// It is not supported in auto-ack mode and by the batch handler.
+func SetAckCoalescing(size int, interval time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.channel.ackSize = size
+		o.channel.ackInterval = interval
+	}
+}
+
+// SetRetryPolicy declares the retry queues and the parking lot of the queue for the Retry action.
+func SetRetryPolicy(p RetryPolicy) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.channel.retry = &p
+	}
+}
+
+// SetPoisonPolicy rejects or parks the deliveries which are redelivered more than the threshold
+// before the handler runs. The redeliveries are counted by the x-delivery-count and x-death headers.
+func SetPoisonPolicy(p PoisonPolicy) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.channel.poison = &p
+	}
+}
+
+// SetErrorPolicy sets the mapping of the errors of the E handler to the actions.
+// The default is DefaultErrorPolicy.
+func SetErrorPolicy(p ErrorPolicy) ConsumerOption {
+	return func(o *consumerOptions) {
+		if p != nil {
+			o.errorPolicy = p
+		}
+	}
+}
+
+// SetPanicAction sets the action of the delivery which handler panicked. The default is Reject.
+func SetPanicAction(a Action) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.panicAction = a
+	}
+}
+
+// SetPanicRecovery enables the recovery of the panics of the handler and the interceptors.
+// The recovery is enabled by default, the panic crashes the process when it is disabled.
+func SetPanicRecovery(enabled bool) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.noRecover = !enabled
+	}
+}
+
+// SetHandlerTimeout sets the timeout of the handler, the context of the handler is done when it is passed.
+// The delivery is settled with the timeout action even if the handler ignores the context,
+// so the handler must not use the delivery after the context is done.
+// The timed out handler is not waited by Consumer.Cancel and Client.Shutdown,
+// it is counted by ConsumerStats.Abandoned until it returns.
+func SetHandlerTimeout(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		if d > 0 {
+			o.handlerTimeout = d
+		}
+	}
+}
+
+// SetTimeoutAction sets the action of the delivery which handler exceeded the timeout. The default is Nack.
+func SetTimeoutAction(a Action) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.timeoutAction = a
+	}
+}
+
+// SetSlowHandlerThreshold sets the duration of the handler after which the warning is logged
+// and counted by ConsumerStats.SlowHandlers.
+func SetSlowHandlerThreshold(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		if d > 0 {
+			o.slowThreshold = d
+		}
+	}
+}
@@ -3,6 +3,7 @@ package amqpx
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,19 @@ func TestConsumerOption(t *testing.T) {
 		DeclareQueue(queueDeclare),
 		DeclareExchange(exchangeDeclare),
 		BindQueue(queueBind),
+		BindExchange(ExchangeBind{Source: "source_value", Destination: "destination_value"}),
+		SetConsumerBackoff(ConstantBackoff{Delay: time.Second}),
+		SetNoLocal(true),
+		SetConsumerPriority(10),
+		SetConsumerTimeout(time.Hour),
+		SetConsumerTimeout(0),
+		SetConsumerArgs(Table{"x-custom": "value"}),
+		SetAckCoalescing(100, time.Millisecond),
+		SetPanicAction(Nack),
+		SetPanicRecovery(false),
+		SetHandlerTimeout(time.Second),
+		SetTimeoutAction(Reject),
+		SetSlowHandlerThreshold(time.Millisecond),
 	} {
 		o(&got)
 	}
@@ -60,10 +74,26 @@ func TestConsumerOption(t *testing.T) {
 			queueDeclare:    &queueDeclare,
 			exchangeDeclare: &exchangeDeclare,
 			queueBind:       &queueBind,
+			exchangeBind:    []ExchangeBind{{Source: "source_value", Destination: "destination_value"}},
+			noLocal:         true,
+			args: Table{
+				ArgConsumerPriority: 10,
+				ArgConsumerTimeout:  int64(3600000),
+				"x-custom":          "value",
+			},
+			ackSize:     100,
+			ackInterval: time.Millisecond,
 		},
 		concurrency: 3,
 		interceptor: nil,
 		unmarshaler: map[string]Unmarshaler{testUnmarshaler.ContentType(): testUnmarshaler},
+		backoff:     ConstantBackoff{Delay: time.Second},
+		panicAction: Nack,
+		noRecover:   true,
+
+		handlerTimeout: time.Second,
+		timeoutAction:  Reject,
+		slowThreshold:  time.Millisecond,
 	}
 	assert.Equal(t, want, got)
 }
@@ -103,6 +133,77 @@ func TestConsumerOption_Validate(t *testing.T) {
 		assert.Nil(t, got)
 	})
 
+	t.Run("stream auto-ack mode", func(t *testing.T) {
+		t.Parallel()
+
+		got := (&consumerOptions{channel: channelOptions{autoAck: true, streamOffset: &StreamOffsetNext}}).validate(fn)
+		assert.ErrorIs(t, got, errStreamAutoAck)
+	})
+
+	t.Run("stream prefetch count", func(t *testing.T) {
+		t.Parallel()
+
+		got := (&consumerOptions{channel: channelOptions{streamOffset: &StreamOffsetNext}}).validate(fn)
+		assert.ErrorIs(t, got, errStreamPrefetch)
+	})
+
+	t.Run("single active consumer", func(t *testing.T) {
+		t.Parallel()
+
+		got := consumerOptions{onActive: func(bool) {}}
+		require.NoError(t, got.validate(fn))
+		assert.Nil(t, got.channel.queueDeclare)
+
+		args := Table{ArgMaxLength: 10}
+		got = consumerOptions{onActive: func(bool) {}, channel: channelOptions{queueDeclare: &QueueDeclare{AutoDelete: true, Args: args}}}
+		require.NoError(t, got.validate(fn))
+		assert.Equal(t, &QueueDeclare{AutoDelete: true, Args: Table{ArgMaxLength: 10, ArgSingleActiveConsumer: true}}, got.channel.queueDeclare)
+		assert.Equal(t, Table{ArgMaxLength: 10}, args)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		t.Parallel()
+
+		batch := B(10, time.Second, func(ctx context.Context, d []*Delivery[[]byte]) []Action { return []Action{Ack} })
+		got := consumerOptions{}
+		require.NoError(t, got.validate(batch))
+		assert.Equal(t, 10, got.channel.prefetchCount)
+		assert.Equal(t, 1, got.concurrency)
+
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{prefetchCount: 5}}).validate(batch), errBatchPrefetch)
+		assert.ErrorIs(t, (&consumerOptions{}).validate(B(0, time.Second, batch.fn)), errBatchLimits)
+		assert.ErrorIs(t, (&consumerOptions{}).validate(B(10, 0, batch.fn)), errBatchLimits)
+	})
+
+	t.Run("ack coalescing", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, (&consumerOptions{channel: channelOptions{ackSize: 10, ackInterval: time.Second}}).validate(fn))
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{ackSize: 10}}).validate(fn), errAckCoalescingLimits)
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{ackSize: 10, ackInterval: time.Second, autoAck: true}}).validate(fn), errAckCoalescingAutoAck)
+
+		batch := B(10, time.Second, func(ctx context.Context, d []*Delivery[[]byte]) []Action { return []Action{Ack} })
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{ackSize: 10, ackInterval: time.Second}}).validate(batch), errAckCoalescingBatch)
+	})
+
+	t.Run("retry policy", func(t *testing.T) {
+		t.Parallel()
+
+		retry := &RetryPolicy{Delays: []time.Duration{time.Second}}
+		require.NoError(t, (&consumerOptions{channel: channelOptions{retry: retry}}).validate(fn))
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{retry: retry, autoAck: true}}).validate(fn), errRetryAutoAck)
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{retry: &RetryPolicy{}}}).validate(fn), errRetryPolicy)
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{retry: &RetryPolicy{Delays: []time.Duration{0}}}}).validate(fn), errRetryPolicy)
+	})
+
+	t.Run("poison policy", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, (&consumerOptions{channel: channelOptions{poison: &PoisonPolicy{}}}).validate(fn))
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{poison: &PoisonPolicy{MaxRedeliveries: -1}}}).validate(fn), errPoisonPolicy)
+		assert.ErrorIs(t, (&consumerOptions{channel: channelOptions{poison: &PoisonPolicy{}, autoAck: true}}).validate(fn), errPoisonAutoAck)
+	})
+
 	t.Run("func nil", func(t *testing.T) {
 		t.Parallel()
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -24,6 +25,18 @@ func (*unmarshaler) Unmarshal(b []byte, v any) error {
 
 var testUnmarshaler = &unmarshaler{}
 
+type marshaler struct{}
+
+func (*marshaler) ContentType() string {
+	return "application/json"
+}
+
+func (*marshaler) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+var testMarshaler = &marshaler{}
+
 func TestConsumer_Reconnect(t *testing.T) {
 	t.Parallel()
 
@@ -31,7 +44,8 @@ func TestConsumer_Reconnect(t *testing.T) {
 	defer client.Close()
 	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
 
-	assert.NoError(t, client.NewConsumer("", D(func(context.Context, *Delivery[[]byte]) Action { return Ack })))
+	_, err := client.NewConsumer("", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }))
+	assert.NoError(t, err)
 	done := make(chan bool)
 	mock.Conn.ChannelFunc = func() (Channel, error) {
 		defer close(done)
@@ -41,6 +55,41 @@ func TestConsumer_Reconnect(t *testing.T) {
 	<-done
 }
 
+func TestConsumer_ReconnectAttempts(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	client, mock := prep(t, UseBackoff(ConstantBackoff{Delay: time.Millisecond, MaxAttempts: 3}))
+	defer client.Close()
+
+	var closed atomic.Bool
+	mock.Conn.IsClosedFunc = func() bool {
+		return closed.Load()
+	}
+	dial := make(chan struct{})
+	mock.Dialer.DialFunc = func(_ context.Context) (Connection, error) {
+		<-dial
+		closed.Store(false)
+		return mock.Conn, nil
+	}
+
+	consumed := make(chan bool, 2)
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		consumed <- true
+		return make(chan amqp091.Delivery), nil
+	}
+	_, err := client.NewConsumer("foo", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }))
+	require.NoError(t, err)
+	<-consumed
+
+	// the attempts are not spent while the client reconnects
+	closed.Store(true)
+	mock.Conn.Close()
+	time.Sleep(20 * time.Millisecond)
+	close(dial)
+	<-consumed
+}
+
 func TestClient_NewConsumer(t *testing.T) {
 	t.Parallel()
 
@@ -55,7 +104,7 @@ func TestClient_NewConsumer(t *testing.T) {
 		}
 
 		queue := "foo"
-		got := client.NewConsumer(queue, D(func(context.Context, *Delivery[[]byte]) Action { return Ack }))
+		_, got := client.NewConsumer(queue, D(func(context.Context, *Delivery[[]byte]) Action { return Ack }))
 		assert.Errorf(t, got, "amqpx: queue %q consumer-tag %q: %s", queue, "", "create channel: failed")
 	})
 
@@ -70,11 +119,216 @@ func TestClient_NewConsumer(t *testing.T) {
 		}
 
 		queue := "foo"
-		got := client.NewConsumer(queue, D(func(context.Context, *Delivery[[]byte]) Action { return Ack }))
+		_, got := client.NewConsumer(queue, D(func(context.Context, *Delivery[[]byte]) Action { return Ack }))
 		assert.Errorf(t, got, "amqpx: queue %q consumer-tag %q: %s", queue, "", "consume: failed")
 	})
 }
 
+func TestClient_NewConsumerBindExchange(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+
+	var got []string
+	mock.Channel.ExchangeBindFunc = func(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+		got = append(got, source+" "+key+" "+destination)
+		return nil
+	}
+
+	_, err := client.NewConsumer("foo", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }),
+		BindExchange(ExchangeBind{Source: "root", Destination: "events", RoutingKey: []string{"a", "b"}}),
+		BindExchange(ExchangeBind{Source: "events", Destination: "orders", RoutingKey: []string{"c"}}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"root a events", "root b events", "events c orders"}, got)
+}
+
+func TestClient_NewConsumerArgs(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+
+	_, err := client.NewConsumer("foo", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }),
+		SetNoLocal(true), SetConsumerPriority(5), SetConsumerTimeout(time.Minute))
+	require.NoError(t, err)
+
+	calls := mock.Channel.ConsumeCalls()
+	require.Len(t, calls, 1)
+	assert.True(t, calls[0].NoLocal)
+	assert.Equal(t, amqp091.Table{ArgConsumerPriority: 5, ArgConsumerTimeout: int64(60000)}, calls[0].Args)
+}
+
+func TestConsumer_SingleActive(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	client, mock := prep(t)
+
+	deliveries := make(chan amqp091.Delivery)
+	mock.Channel.QueueDeclareFunc = func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+		return amqp091.Queue{}, nil
+	}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return deliveries, nil
+	}
+
+	active := make(chan bool, 1)
+	_, err := client.NewConsumer("foo", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }),
+		DeclareQueue(QueueDeclare{Durable: true}), SetSingleActiveConsumer(func(v bool) { active <- v }))
+	require.NoError(t, err)
+	assert.Equal(t, amqp091.Table{ArgSingleActiveConsumer: true}, mock.Channel.QueueDeclareCalls()[0].Args)
+
+	// the last delivery can be requeued by closing
+	ack := &AcknowledgerMock{
+		AckFunc:  func(tag uint64, multiple bool) error { return nil },
+		NackFunc: func(tag uint64, multiple bool, requeue bool) error { return nil },
+	}
+	deliveries <- amqp091.Delivery{Acknowledger: ack}
+	assert.True(t, <-active)
+	deliveries <- amqp091.Delivery{Acknowledger: ack}
+
+	// the consumer goes standby when the channel is lost
+	mock.Channel.Close()
+	assert.False(t, <-active)
+
+	deliveries <- amqp091.Delivery{Acknowledger: ack}
+	assert.True(t, <-active)
+
+	client.Close()
+	assert.False(t, <-active)
+}
+
+func TestConsumer_PauseResume(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	events := make(chan string, 1)
+	var delivery chan amqp091.Delivery
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		delivery = make(chan amqp091.Delivery)
+		events <- "consume"
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		events <- "cancel"
+		return nil
+	}
+
+	cons, err := client.NewConsumer("foo", D(func(context.Context, *Delivery[[]byte]) Action { return Ack }), ConsumerTag("bar"))
+	require.NoError(t, err)
+	assert.Equal(t, "foo", cons.Queue())
+	assert.Equal(t, "bar", cons.Tag())
+	require.Equal(t, "consume", <-events)
+
+	cons.Pause()
+	require.Equal(t, "cancel", <-events)
+	cons.Resume()
+	require.Equal(t, "consume", <-events)
+
+	// the channel is kept
+	assert.Equal(t, 1, len(mock.Conn.ChannelCalls()))
+	assert.Equal(t, 0, len(mock.Channel.CloseCalls()))
+}
+
+func TestConsumer_Cancel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("in-flight", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := prep(t)
+		defer client.Close()
+		defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+		ack := &AcknowledgerMock{
+			AckFunc:    func(tag uint64, multiple bool) error { return nil },
+			RejectFunc: func(tag uint64, requeue bool) error { return nil },
+		}
+		delivery := make(chan amqp091.Delivery, 2)
+		delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1}
+		delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 2}
+		mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+			return delivery, nil
+		}
+		mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+			close(delivery)
+			return nil
+		}
+
+		started, release := make(chan bool, 2), make(chan bool)
+		cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+			started <- true
+			<-release
+			assert.NoError(t, ctx.Err())
+			if d.Req.in.DeliveryTag == 1 {
+				return Ack
+			}
+			return Reject
+		}), SetPrefetchCount(2))
+		require.NoError(t, err)
+		<-started
+		<-started
+		assert.Equal(t, 2, cons.Stats().InFlight)
+
+		go func() {
+			time.Sleep(time.Millisecond * 10)
+			close(release)
+		}()
+		require.NoError(t, cons.Cancel(context.Background()))
+
+		stats := cons.Stats()
+		assert.Equal(t, 0, stats.InFlight)
+		assert.Equal(t, uint64(1), stats.Acked)
+		assert.Equal(t, uint64(1), stats.Rejected)
+		assert.Equal(t, uint64(0), stats.Nacked)
+		assert.GreaterOrEqual(t, stats.MaxHandlerLatency, stats.HandlerLatency)
+		assert.NotZero(t, stats.HandlerLatency)
+
+		assert.Equal(t, 1, len(mock.Channel.CancelCalls()))
+		assert.Equal(t, 1, len(mock.Channel.CloseCalls()))
+		assert.Equal(t, StateConnected, client.State())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := prep(t)
+		defer client.Close()
+		defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+		delivery := make(chan amqp091.Delivery, 1)
+		delivery <- amqp091.Delivery{Acknowledger: &AcknowledgerMock{
+			AckFunc: func(tag uint64, multiple bool) error { return nil },
+		}}
+		mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+			return delivery, nil
+		}
+		mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+			close(delivery)
+			return nil
+		}
+
+		started := make(chan bool)
+		cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+			close(started)
+			<-ctx.Done()
+			return Ack
+		}))
+		require.NoError(t, err)
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+		defer cancel()
+		assert.ErrorIs(t, cons.Cancel(ctx), context.DeadlineExceeded)
+		assert.Equal(t, 1, len(mock.Channel.CloseCalls()))
+	})
+}
+
 func TestDeliveryRequest_setStatus(t *testing.T) {
 	t.Parallel()
 
@@ -143,7 +397,7 @@ func TestDeliveryBytes(t *testing.T) {
 	}
 
 	done := make(chan bool)
-	got := client.NewConsumer("", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+	_, got := client.NewConsumer("", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
 		defer close(done)
 		assert.Equal(t, &msg.Body, d.Msg)
 		return Ack
@@ -167,7 +421,7 @@ func TestDeliveryStruct(t *testing.T) {
 		}}, got)
 		return Ack
 	})
-	fn.init(map[string]Unmarshaler{testUnmarshaler.ContentType(): testUnmarshaler})
+	fn.init(map[string]Unmarshaler{testUnmarshaler.ContentType(): testUnmarshaler}, DefaultErrorPolicy)
 	got := fn.serve(context.Background(), &DeliveryRequest{in: &amqp091.Delivery{
 		Body:        []byte(`{"name":"gopher"}`),
 		ContentType: testUnmarshaler.ContentType()},
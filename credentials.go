@@ -0,0 +1,72 @@
+package amqpx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultCredentialsRefresh = time.Minute
+
+// A Credentials represents username and password of the connection.
+type Credentials struct {
+	Username string
+	// Password is the password or the token (ex: OAuth2 backend).
+	Password string
+	// ExpiresAt is the expiration time of the credentials, zero means no expiration.
+	ExpiresAt time.Time
+}
+
+// A CredentialsProvider is an interface implemented by the source of the rotating credentials.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (Credentials, error)
+}
+
+// CredentialsFunc type is an adapter to allow the use of ordinary functions as CredentialsProvider.
+type CredentialsFunc func(ctx context.Context) (Credentials, error)
+
+// Credentials calls f(ctx).
+func (f CredentialsFunc) Credentials(ctx context.Context) (Credentials, error) {
+	return f(ctx)
+}
+
+// A credentialsSource holds the credentials which was used by the last dial.
+type credentialsSource struct {
+	provider CredentialsProvider
+
+	mx      sync.Mutex
+	current Credentials
+}
+
+// get returns the fresh credentials.
+func (s *credentialsSource) get(ctx context.Context) (Credentials, error) {
+	c, err := s.provider.Credentials(ctx)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	s.current = c
+	return c, nil
+}
+
+func (s *credentialsSource) last() Credentials {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+	return s.current
+}
+
+// refreshDelay returns delay before the credentials are refreshed.
+// The credentials are refreshed a minute before expiration or at half of the rest lifetime when it is short.
+func refreshDelay(expiresAt time.Time) time.Duration {
+	until := time.Until(expiresAt)
+	if delay := until - defaultCredentialsRefresh; delay > until/2 {
+		return delay
+	}
+
+	if until < 0 {
+		return 0
+	}
+	return until / 2
+}
@@ -0,0 +1,74 @@
+package amqpx
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshDelay(t *testing.T) {
+	t.Parallel()
+
+	got := refreshDelay(time.Now().Add(time.Hour))
+	assert.True(t, got > time.Hour-defaultCredentialsRefresh-time.Second && got <= time.Hour-defaultCredentialsRefresh, got)
+
+	got = refreshDelay(time.Now().Add(time.Minute))
+	assert.True(t, got > time.Minute/2-time.Second && got <= time.Minute/2, got)
+
+	assert.Equal(t, time.Duration(0), refreshDelay(time.Now().Add(-time.Minute)))
+}
+
+func TestClient_RefreshCredentials(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	t.Run("update secret", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := prep(t, UseCredentials(CredentialsFunc(func(context.Context) (Credentials, error) {
+			return Credentials{Username: "user", Password: "token", ExpiresAt: time.Now().Add(time.Millisecond * 10)}, nil
+		})))
+		defer client.Close()
+
+		updated := make(chan string, 1)
+		mock.Conn.UpdateSecretFunc = func(newSecret string, reason string) error {
+			select {
+			case updated <- newSecret:
+			default:
+			}
+			return nil
+		}
+
+		_, err := client.credentials.get(context.Background())
+		require.NoError(t, err)
+		go client.refreshCredentials(mock.Conn, make(chan *amqp091.Error))
+		assert.Equal(t, "token", <-updated)
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		t.Parallel()
+
+		var n atomic.Int32
+		client, mock := prep(t, WithLog(NoOpLogger), UseCredentials(CredentialsFunc(func(context.Context) (Credentials, error) {
+			return Credentials{Username: fmt.Sprintf("user%d", n.Add(1)), ExpiresAt: time.Now().Add(time.Millisecond * 10)}, nil
+		})))
+		defer client.Close()
+
+		reconnect := make(chan bool)
+		mock.Dialer.DialFunc = func(_ context.Context) (Connection, error) {
+			close(reconnect)
+			return nil, fmt.Errorf("failed")
+		}
+
+		_, err := client.credentials.get(context.Background())
+		require.NoError(t, err)
+		go client.refreshCredentials(mock.Conn, make(chan *amqp091.Error))
+		<-reconnect
+	})
+}
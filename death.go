@@ -0,0 +1,153 @@
+package amqpx
+
+import (
+	"context"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// The headers of the redelivered messages.
+const (
+	HeaderDeath         = "x-death"
+	HeaderDeliveryCount = "x-delivery-count"
+)
+
+// DeathReason is the reason of the dead-lettering.
+type DeathReason string
+
+const (
+	// DeathRejected is the message rejected or nacked without requeue.
+	DeathRejected DeathReason = "rejected"
+	// DeathExpired is the expired message TTL.
+	DeathExpired DeathReason = "expired"
+	// DeathMaxLen is the exceeded max length of the queue.
+	DeathMaxLen DeathReason = "maxlen"
+	// DeathDeliveryLimit is the exceeded delivery limit of the quorum queue.
+	DeathDeliveryLimit DeathReason = "delivery_limit"
+)
+
+// A Death represents the dead-lettering of the message from the queue.
+type Death struct {
+	Queue       string
+	Reason      DeathReason
+	Count       int64
+	Exchange    string
+	RoutingKeys []string
+	// Time is the time of the first dead-lettering from the queue for the reason.
+	Time time.Time
+}
+
+// DeathHistory returns the dead-lettering history of the message, the most recent is first.
+func (d *DeliveryRequest) DeathHistory() []Death {
+	deaths, _ := d.in.Headers[HeaderDeath].([]any)
+	history := make([]Death, 0, len(deaths))
+	for _, v := range deaths {
+		t, ok := v.(amqp091.Table)
+		if !ok {
+			continue
+		}
+
+		death := Death{
+			Queue:    headerString(t, "queue"),
+			Reason:   DeathReason(headerString(t, "reason")),
+			Count:    headerInt(t, "count"),
+			Exchange: headerString(t, "exchange"),
+		}
+		death.Time, _ = t["time"].(time.Time)
+		keys, _ := t["routing-keys"].([]any)
+		for _, k := range keys {
+			if s, ok := k.(string); ok {
+				death.RoutingKeys = append(death.RoutingKeys, s)
+			}
+		}
+		history = append(history, death)
+	}
+	return history
+}
+
+// DeliveryCount returns the number of the failed deliveries of the message in the quorum queue.
+func (d *DeliveryRequest) DeliveryCount() (int64, bool) {
+	if _, ok := d.in.Headers[HeaderDeliveryCount]; !ok {
+		return 0, false
+	}
+	return headerInt(d.in.Headers, HeaderDeliveryCount), true
+}
+
+// Redeliveries returns the number of the previous deliveries of the message. It is the greatest of
+// the quorum queue delivery count and the count of the rejected dead-lettering, at least 1 if the message is redelivered.
+//
+// The expired dead-lettering is not counted, so the delayed retries of RetryPolicy
+// are limited by RetryPolicy.MaxAttempts and do not trip PoisonPolicy.
+func (d *DeliveryRequest) Redeliveries() int64 {
+	n, _ := d.DeliveryCount()
+
+	var deaths int64
+	for _, v := range d.DeathHistory() {
+		if v.Reason == DeathRejected || v.Reason == DeathDeliveryLimit {
+			deaths += v.Count
+		}
+	}
+
+	n = max(n, deaths)
+	if n == 0 && d.in.Redelivered {
+		return 1
+	}
+	return n
+}
+
+func headerString(t amqp091.Table, key string) string {
+	s, _ := t[key].(string)
+	return s
+}
+
+func headerInt(t amqp091.Table, key string) int64 {
+	switch v := t[key].(type) {
+	case int64:
+		return v
+
+	case int32:
+		return int64(v)
+
+	case int:
+		return int64(v)
+	}
+	return 0
+}
+
+// A PoisonPolicy represents the handling of the deliveries which are redelivered too many times.
+// The poison delivery is rejected or moved to the parking lot queue before the handler runs.
+type PoisonPolicy struct {
+	// MaxRedeliveries is the threshold of DeliveryRequest.Redeliveries,
+	// the retries of RetryPolicy are not counted.
+	MaxRedeliveries int64
+	// ParkingLot is the queue of the poison messages, the delivery is rejected when it is empty.
+	ParkingLot string
+}
+
+// checkPoison returns the action of the poison delivery, false if the delivery must be handled.
+func (c *consumer) checkPoison(ctx context.Context, channel Channel, d *amqp091.Delivery, req *DeliveryRequest) (Action, bool) {
+	p := c.opts.poison
+	if p == nil {
+		return 0, false
+	}
+
+	n := req.Redeliveries()
+	if n <= p.MaxRedeliveries {
+		return 0, false
+	}
+
+	log := req.Logger().With("redeliveries", n)
+	if p.ParkingLot == "" {
+		log.Warn("reject poison delivery")
+		return Reject, true
+	}
+
+	if err := publishCopy(ctx, channel, p.ParkingLot, d, d.Headers); err != nil {
+		log.Error("requeue poison delivery", LogKeyError, err)
+		return Nack, true
+	}
+
+	log.Warn("park poison delivery", LogKeyQueue, p.ParkingLot)
+	return Ack, true
+}
@@ -0,0 +1,187 @@
+package amqpx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeliveryRequest_DeathHistory(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	req := &DeliveryRequest{in: &amqp091.Delivery{Headers: amqp091.Table{
+		HeaderDeath: []any{
+			amqp091.Table{
+				"queue":        "foo.retry.1s",
+				"reason":       "expired",
+				"count":        int64(3),
+				"exchange":     "",
+				"routing-keys": []any{"foo.retry.1s"},
+				"time":         now,
+			},
+			amqp091.Table{
+				"queue":        "foo",
+				"reason":       "rejected",
+				"count":        int64(2),
+				"exchange":     "orders",
+				"routing-keys": []any{"created", "updated"},
+				"time":         now,
+			},
+			"unknown",
+		},
+		HeaderDeliveryCount: int64(4),
+	}}}
+
+	assert.Equal(t, []Death{
+		{Queue: "foo.retry.1s", Reason: DeathExpired, Count: 3, RoutingKeys: []string{"foo.retry.1s"}, Time: now},
+		{Queue: "foo", Reason: DeathRejected, Count: 2, Exchange: "orders", RoutingKeys: []string{"created", "updated"}, Time: now},
+	}, req.DeathHistory())
+
+	count, ok := req.DeliveryCount()
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, int64(4), req.Redeliveries())
+}
+
+func TestDeliveryRequest_Redeliveries(t *testing.T) {
+	t.Parallel()
+
+	req := &DeliveryRequest{in: &amqp091.Delivery{}}
+	assert.Empty(t, req.DeathHistory())
+	_, ok := req.DeliveryCount()
+	assert.False(t, ok)
+	assert.Equal(t, int64(0), req.Redeliveries())
+
+	req = &DeliveryRequest{in: &amqp091.Delivery{Redelivered: true}}
+	assert.Equal(t, int64(1), req.Redeliveries())
+
+	req = &DeliveryRequest{in: &amqp091.Delivery{Redelivered: true, Headers: amqp091.Table{HeaderDeliveryCount: int32(7)}}}
+	assert.Equal(t, int64(7), req.Redeliveries())
+
+	// the expired deaths of the retry queues are not counted
+	req = &DeliveryRequest{in: &amqp091.Delivery{Headers: amqp091.Table{HeaderDeath: []any{
+		amqp091.Table{"queue": "foo.retry.1s", "reason": "expired", "count": int64(5)},
+		amqp091.Table{"queue": "foo", "reason": "rejected", "count": int64(2)},
+		amqp091.Table{"queue": "foo", "reason": "delivery_limit", "count": int64(1)},
+	}}}}
+	assert.Equal(t, int64(3), req.Redeliveries())
+}
+
+func TestConsumer_PoisonRetry(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error { return nil },
+	}
+	delivery := make(chan amqp091.Delivery, 1)
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1, Headers: amqp091.Table{
+		HeaderRetryAttempt: int64(3),
+		HeaderDeath: []any{
+			amqp091.Table{"queue": "foo.retry.1s", "reason": "expired", "count": int64(3)},
+		},
+	}}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+	mock.Channel.QueueDeclareFunc = func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+		return amqp091.Queue{}, nil
+	}
+	mock.Channel.ConfirmFunc = func(noWait bool) error {
+		return nil
+	}
+
+	// the retries are limited by the retry policy, not by the poison policy
+	handled := make(chan uint64, 1)
+	cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		handled <- d.Req.DeliveryTag()
+		return Ack
+	}), SetRetryPolicy(RetryPolicy{Delays: []time.Duration{time.Second}, MaxAttempts: 5}),
+		SetPoisonPolicy(PoisonPolicy{MaxRedeliveries: 2}))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), <-handled)
+	require.NoError(t, cons.Cancel(context.Background()))
+	assert.Empty(t, mock.Channel.PublishWithDeferredConfirmWithContextCalls())
+}
+
+func TestConsumer_Poison(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		parkingLot string
+	}{
+		{name: "reject"},
+		{name: "park", parkingLot: "poison"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, mock := prep(t)
+			defer client.Close()
+			defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+			ack := &AcknowledgerMock{
+				AckFunc:    func(tag uint64, multiple bool) error { return nil },
+				RejectFunc: func(tag uint64, requeue bool) error { return nil },
+			}
+			delivery := make(chan amqp091.Delivery, 2)
+			delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1, Headers: amqp091.Table{HeaderDeliveryCount: int64(3)}, Body: []byte("poison")}
+			delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 2, Headers: amqp091.Table{HeaderDeliveryCount: int64(2)}}
+			mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+				return delivery, nil
+			}
+			mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+				close(delivery)
+				return nil
+			}
+			mock.Channel.QueueDeclareFunc = func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+				return amqp091.Queue{}, nil
+			}
+			mock.Channel.ConfirmFunc = func(noWait bool) error {
+				return nil
+			}
+			mock.Channel.PublishWithDeferredConfirmWithContextFunc = func(ctx context.Context, exchange string, key string, mandatory bool, immediate bool, msg amqp091.Publishing) (*amqp091.DeferredConfirmation, error) {
+				return nil, nil
+			}
+
+			handled := make(chan uint64, 2)
+			cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+				handled <- d.Req.DeliveryTag()
+				return Ack
+			}), SetPoisonPolicy(PoisonPolicy{MaxRedeliveries: 2, ParkingLot: tt.parkingLot}), SetPrefetchCount(1))
+			require.NoError(t, err)
+			assert.Equal(t, uint64(2), <-handled)
+			require.NoError(t, cons.Cancel(context.Background()))
+			assert.Empty(t, handled)
+
+			if tt.parkingLot == "" {
+				assert.Equal(t, uint64(1), ack.RejectCalls()[0].Tag)
+				assert.Empty(t, mock.Channel.PublishWithDeferredConfirmWithContextCalls())
+				return
+			}
+
+			assert.Equal(t, "poison", mock.Channel.QueueDeclareCalls()[0].Name)
+			published := mock.Channel.PublishWithDeferredConfirmWithContextCalls()
+			require.Len(t, published, 1)
+			assert.Equal(t, "poison", published[0].Key)
+			assert.Equal(t, []byte("poison"), published[0].Msg.Body)
+			assert.Len(t, ack.AckCalls(), 2)
+		})
+	}
+}
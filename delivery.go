@@ -2,6 +2,7 @@ package amqpx
 
 import (
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/rabbitmq/amqp091-go"
@@ -29,15 +30,21 @@ const (
 
 	// Reject is explicit not acknowledged and do not requeue.
 	Reject
+
+	// Retry publishes the copy of the message to the delayed retry queue and acknowledges the delivery,
+	// the message is moved to the parking lot queue when the attempts are exceeded.
+	// The consumer requires SetRetryPolicy, otherwise the delivery is requeued as Nack.
+	Retry
 )
 
 type DeliveryRequest struct {
 	in     *amqp091.Delivery
 	status Action
-	log    LogFunc
+	err    error
+	log    *slog.Logger
 }
 
-func newDeliveryRequest(req *amqp091.Delivery, l LogFunc) *DeliveryRequest {
+func newDeliveryRequest(req *amqp091.Delivery, l *slog.Logger) *DeliveryRequest {
 	if req.Headers == nil {
 		req.Headers = make(amqp091.Table)
 	}
@@ -153,13 +160,45 @@ func (d *DeliveryRequest) SetBody(b []byte) {
 	d.in.Body = b
 }
 
+// StreamOffset returns the offset of the message in the stream queue.
+func (d *DeliveryRequest) StreamOffset() (int64, bool) {
+	return streamOffset(d.in.Headers)
+}
+
+// Err returns the error of the handler, the unmarshaling or the recovered panic, it can be used by the interceptors.
+func (d *DeliveryRequest) Err() error {
+	return d.err
+}
+
+// SetErr sets the error of the delivery, it is used by the handlers and interceptors which report the cause of the failure.
+func (d *DeliveryRequest) SetErr(err error) {
+	d.err = err
+}
+
 // Status returns acknowledgement status.
 func (d *DeliveryRequest) Status() Action {
 	return d.status
 }
 
+// Logger returns the consumer's logger with the attributes of the delivery.
+func (d *DeliveryRequest) Logger() *slog.Logger {
+	if d.log == nil {
+		return discardLogger
+	}
+
+	return d.log.With(
+		LogKeyExchange, d.in.Exchange,
+		LogKeyRoutingKey, d.in.RoutingKey,
+		LogKeyDeliveryTag, d.in.DeliveryTag,
+		"content_type", d.in.ContentType,
+	)
+}
+
+// Log logs the printf message, the level is parsed from the prefix (ex: "[ERROR] ").
+//
+// Deprecated: use Logger.
 func (d *DeliveryRequest) Log(format string, v ...any) {
-	d.log(format, v...)
+	logf(d.Logger(), format, v...)
 }
 
 func (d *DeliveryRequest) setStatus(status Action) error {
@@ -178,6 +217,32 @@ func (d *DeliveryRequest) setStatus(status Action) error {
 	}
 }
 
+// setStatusMultiple acknowledges the delivery and all unacknowledged deliveries before it on the channel.
+func (d *DeliveryRequest) setStatusMultiple(status Action) error {
+	var err error
+	switch status {
+	case Ack:
+		err = d.in.Acknowledger.Ack(d.in.DeliveryTag, true)
+
+	case Nack:
+		err = d.in.Acknowledger.Nack(d.in.DeliveryTag, true, true)
+
+	case Reject:
+		// reject does not support the multiple flag
+		err = d.in.Acknowledger.Nack(d.in.DeliveryTag, true, false)
+
+	default:
+		return fmt.Errorf("delivery has unknown ack mode \"%d\"", status)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.status = status
+	return nil
+}
+
 func (d *DeliveryRequest) ack() error {
 	if err := d.in.Acknowledger.Ack(d.in.DeliveryTag, false); err != nil {
 		return err
@@ -205,10 +270,6 @@ func (d *DeliveryRequest) reject() error {
 	return nil
 }
 
-func (d *DeliveryRequest) info() string {
-	return fmt.Sprintf("exchange %q routing-key %q content-type %q", d.in.Exchange, d.in.RoutingKey, d.in.ContentType)
-}
-
 // A Delivery represent the fields for a delivered message.
 type Delivery[T any] struct {
 	Msg *T
@@ -3,6 +3,12 @@ package amqpx
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"math/rand"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/rabbitmq/amqp091-go"
@@ -12,7 +18,9 @@ const (
 	defaultHost           = "localhost"
 	defaultPort           = 5672
 	defaultReconnectDelay = 4 * time.Second
+	defaultNodeCooldown   = 30 * time.Second
 	defaultHeartbeat      = 10 * time.Second
+	defaultDialTimeout    = 30 * time.Second
 	defaultLocale         = "en_US"
 )
 
@@ -23,6 +31,8 @@ type Connection interface {
 	IsClosed() bool
 	Channel() (Channel, error)
 	NotifyClose(chan *amqp091.Error) chan *amqp091.Error
+	NotifyBlocked(chan amqp091.Blocking) chan amqp091.Blocking
+	UpdateSecret(newSecret, reason string) error
 	Close() error
 }
 
@@ -31,25 +41,42 @@ type Connection interface {
 // A Channel is an interface implemented by amqp091 client.
 type Channel interface {
 	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp091.Table) (amqp091.Queue, error)
+	QueueDeclarePassive(name string, durable, autoDelete, exclusive, noWait bool, args amqp091.Table) (amqp091.Queue, error)
+	QueuePurge(name string, noWait bool) (int, error)
+	QueueDelete(name string, ifUnused, ifEmpty, noWait bool) (int, error)
 	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp091.Table) error
+	ExchangeDeclarePassive(name, kind string, durable, autoDelete, internal, noWait bool, args amqp091.Table) error
+	ExchangeDelete(name string, ifUnused, noWait bool) error
 	QueueBind(name, key, exchange string, noWait bool, args amqp091.Table) error
+	QueueUnbind(name, key, exchange string, args amqp091.Table) error
+	ExchangeBind(destination, key, source string, noWait bool, args amqp091.Table) error
+	ExchangeUnbind(destination, key, source string, noWait bool, args amqp091.Table) error
 	Confirm(noWait bool) error
 	Qos(prefetchCount, prefetchSize int, global bool) error
 	NotifyClose(chan *amqp091.Error) chan *amqp091.Error
 	NotifyCancel(chan string) chan string
 	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error)
+	Cancel(consumer string, noWait bool) error
 	PublishWithDeferredConfirmWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp091.Publishing) (*amqp091.DeferredConfirmation, error)
 	NotifyReturn(c chan amqp091.Return) chan amqp091.Return
 	Close() error
 }
 
-//go:generate ./bin/moq -out dialer_moq_test.go . dialer
+//go:generate ./bin/moq -rm -out dialer_moq_test.go . Dialer
 
-// A dialer is an interface implemented by creating connection.
-type dialer interface {
+// A Dialer is an interface implemented by creating connection (ex: proxies, unix sockets, test transports).
+// Dial is called on connect and every reconnect, it should retry until ctx is done,
+// the client is stopped when the error is returned on reconnect.
+type Dialer interface {
 	Dial(context.Context) (Connection, error)
 }
 
+// NewConnection returns Connection of amqp091 client.
+// It is used by custom Dialer.
+func NewConnection(conn *amqp091.Connection) Connection {
+	return &amqpConn{conn: conn}
+}
+
 type amqpConn struct {
 	conn *amqp091.Connection
 }
@@ -66,6 +93,14 @@ func (w *amqpConn) NotifyClose(receiver chan *amqp091.Error) chan *amqp091.Error
 	return w.conn.NotifyClose(receiver)
 }
 
+func (w *amqpConn) NotifyBlocked(receiver chan amqp091.Blocking) chan amqp091.Blocking {
+	return w.conn.NotifyBlocked(receiver)
+}
+
+func (w *amqpConn) UpdateSecret(newSecret, reason string) error {
+	return w.conn.UpdateSecret(newSecret, reason)
+}
+
 func (w *amqpConn) Close() error {
 	return w.conn.Close()
 }
@@ -86,24 +121,166 @@ var defaultConfig = amqp091.Config{
 }
 
 type defaultDialer struct {
-	URI    string
-	Config amqp091.Config
-	log    LogFunc
+	nodes   []*dialNode
+	Config  amqp091.Config
+	random  bool
+	backoff Backoff
+	log     *slog.Logger
+	limiter logLimiter
+
+	// credentials overrides username and password of the nodes when it is set
+	credentials *credentialsSource
+
+	mx   sync.Mutex
+	next int
+	dial func(url string, config amqp091.Config) (*amqp091.Connection, error)
+}
+
+// A dialNode represents a node of the cluster with its health memory.
+type dialNode struct {
+	uri      amqp091.URI
+	failedAt time.Time
+}
+
+func (n *dialNode) addr() string {
+	return net.JoinHostPort(n.uri.Host, strconv.Itoa(n.uri.Port))
+}
+
+func newDefaultDialer(uris []amqp091.URI, config amqp091.Config, random bool, backoff Backoff, log *slog.Logger) *defaultDialer {
+	nodes := make([]*dialNode, len(uris))
+	for i, u := range uris {
+		nodes[i] = &dialNode{uri: u}
+	}
+
+	return &defaultDialer{
+		nodes:   nodes,
+		Config:  config,
+		random:  random,
+		backoff: backoff,
+		log:     log,
+		dial:    amqp091.DialConfig,
+	}
 }
 
 func (d *defaultDialer) Dial(ctx context.Context) (Connection, error) {
-	for {
-		conn, err := amqp091.DialConfig(d.URI, d.Config)
-		if err == nil {
-			return &amqpConn{conn: conn}, nil
+	for attempt := 1; ; attempt++ {
+		var err error
+		for _, node := range d.order() {
+			uri := node.uri
+			if d.credentials != nil {
+				var creds Credentials
+				if creds, err = d.credentials.get(ctx); err != nil {
+					logReconnectError(d.log, &d.limiter, "dial conn: credentials", attempt, err)
+					break
+				}
+				uri.Username, uri.Password = creds.Username, creds.Password
+			}
+
+			var conn *amqp091.Connection
+			if conn, err = d.dialContext(ctx, uri.String()); err == nil {
+				d.markHealthy(node)
+				d.limiter.reset()
+				d.log.Info("connected to node", "node", node.addr())
+				return &amqpConn{conn: conn}, nil
+			}
+
+			d.markFailed(node)
+			logReconnectError(d.log.With("node", node.addr()), &d.limiter, "dial conn", attempt, err)
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		delay, ok := d.backoff.Next(attempt, err)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errMaxAttempts, err)
 		}
-		d.log("[ERROR] dial conn: %v", err)
 
 		select {
 		case <-ctx.Done():
 			return nil, fmt.Errorf("%s: %w", err, ctx.Err())
 
-		case <-time.After(defaultReconnectDelay):
+		case <-time.After(delay):
+		}
+	}
+}
+
+// dialContext opens the connection limited by ctx. The deadline of ctx limits the TCP dial
+// and the TLS and AMQP handshakes, the custom dial of the config is not limited.
+func (d *defaultDialer) dialContext(ctx context.Context, url string) (*amqp091.Connection, error) {
+	config := d.Config
+	if config.Dial != nil {
+		return d.dial(url, config)
+	}
+
+	var stop func() bool
+	config.Dial = func(network, addr string) (net.Conn, error) {
+		conn, err := (&net.Dialer{Timeout: defaultDialTimeout}).DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		// the deadline is cleared when the connection is opened
+		deadline := time.Now().Add(defaultDialTimeout)
+		if v, ok := ctx.Deadline(); ok && v.Before(deadline) {
+			deadline = v
 		}
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		// the handshake is interrupted when ctx is done
+		stop = context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+		return conn, nil
+	}
+
+	conn, err := d.dial(url, config)
+	if stop != nil && !stop() && err == nil {
+		// ctx is done after the handshake, the connection can be broken by the deadline
+		conn.Close()
+		return nil, ctx.Err()
 	}
+	return conn, err
+}
+
+// order returns the nodes in order of dialing.
+// The nodes are walked round-robin (or randomized) and the nodes which failed recently go last.
+func (d *defaultDialer) order() []*dialNode {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+
+	nodes := make([]*dialNode, 0, len(d.nodes))
+	if d.random {
+		for _, i := range rand.Perm(len(d.nodes)) {
+			nodes = append(nodes, d.nodes[i])
+		}
+	} else {
+		for i := range d.nodes {
+			nodes = append(nodes, d.nodes[(d.next+i)%len(d.nodes)])
+		}
+		d.next = (d.next + 1) % len(d.nodes)
+	}
+
+	now := time.Now()
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].healthy(now) && !nodes[j].healthy(now)
+	})
+	return nodes
+}
+
+func (d *defaultDialer) markHealthy(n *dialNode) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	n.failedAt = time.Time{}
+}
+
+func (d *defaultDialer) markFailed(n *dialNode) {
+	d.mx.Lock()
+	defer d.mx.Unlock()
+	n.failedAt = time.Now()
+}
+
+func (n *dialNode) healthy(now time.Time) bool {
+	return n.failedAt.IsZero() || now.Sub(n.failedAt) > defaultNodeCooldown
 }
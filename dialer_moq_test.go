@@ -8,26 +8,26 @@ import (
 	"sync"
 )
 
-// Ensure, that dialerMock does implement dialer.
+// Ensure, that DialerMock does implement Dialer.
 // If this is not the case, regenerate this file with moq.
-var _ dialer = &dialerMock{}
+var _ Dialer = &DialerMock{}
 
-// dialerMock is a mock implementation of dialer.
+// DialerMock is a mock implementation of Dialer.
 //
-//	func TestSomethingThatUsesdialer(t *testing.T) {
+//	func TestSomethingThatUsesDialer(t *testing.T) {
 //
-//		// make and configure a mocked dialer
-//		mockeddialer := &dialerMock{
+//		// make and configure a mocked Dialer
+//		mockedDialer := &DialerMock{
 //			DialFunc: func(contextMoqParam context.Context) (Connection, error) {
 //				panic("mock out the Dial method")
 //			},
 //		}
 //
-//		// use mockeddialer in code that requires dialer
+//		// use mockedDialer in code that requires Dialer
 //		// and then make assertions.
 //
 //	}
-type dialerMock struct {
+type DialerMock struct {
 	// DialFunc mocks the Dial method.
 	DialFunc func(contextMoqParam context.Context) (Connection, error)
 
@@ -43,9 +43,9 @@ type dialerMock struct {
 }
 
 // Dial calls DialFunc.
-func (mock *dialerMock) Dial(contextMoqParam context.Context) (Connection, error) {
+func (mock *DialerMock) Dial(contextMoqParam context.Context) (Connection, error) {
 	if mock.DialFunc == nil {
-		panic("dialerMock.DialFunc: method is nil but dialer.Dial was just called")
+		panic("DialerMock.DialFunc: method is nil but Dialer.Dial was just called")
 	}
 	callInfo := struct {
 		ContextMoqParam context.Context
@@ -61,8 +61,8 @@ func (mock *dialerMock) Dial(contextMoqParam context.Context) (Connection, error
 // DialCalls gets all the calls that were made to Dial.
 // Check the length with:
 //
-//	len(mockeddialer.DialCalls())
-func (mock *dialerMock) DialCalls() []struct {
+//	len(mockedDialer.DialCalls())
+func (mock *DialerMock) DialCalls() []struct {
 	ContextMoqParam context.Context
 } {
 	var calls []struct {
@@ -0,0 +1,95 @@
+package amqpx
+
+import (
+	"context"
+	"errors"
+)
+
+// An ErrorPolicy maps the error of the handler to the action.
+type ErrorPolicy func(err error) Action
+
+// DefaultErrorPolicy rejects the permanent errors and retries the temporary errors,
+// the other errors are requeued.
+func DefaultErrorPolicy(err error) Action {
+	switch {
+	case IsPermanent(err):
+		return Reject
+
+	case IsTemporary(err):
+		return Retry
+
+	default:
+		return Nack
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks the error which is not fixed by the redelivery, the delivery is rejected by DefaultErrorPolicy.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent returns true if the error is marked by Permanent.
+func IsPermanent(err error) bool {
+	var e *permanentError
+	return errors.As(err, &e)
+}
+
+type temporaryError struct {
+	err error
+}
+
+func (e *temporaryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *temporaryError) Unwrap() error {
+	return e.err
+}
+
+// Temporary marks the error which can be fixed later, the delivery is retried by DefaultErrorPolicy.
+func Temporary(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &temporaryError{err: err}
+}
+
+// IsTemporary returns true if the error is marked by Temporary.
+func IsTemporary(err error) bool {
+	var e *temporaryError
+	return errors.As(err, &e)
+}
+
+// E represents handler of consume amqpx.Delivery[T] which returns an error.
+// The delivery is acknowledged when the error is nil, otherwise the error is mapped
+// to the action by the error policy of the consumer and is available by DeliveryRequest.Err.
+func E[T any](fn func(ctx context.Context, d *Delivery[T]) error) *handleValue[T] {
+	v := D[T](nil)
+	v.fn = func(ctx context.Context, d *Delivery[T]) Action {
+		err := fn(ctx, d)
+		if err == nil {
+			return Ack
+		}
+
+		d.Req.err = err
+		action := v.errorPolicy(err)
+		d.Req.Logger().Error("handle delivery", "action", action, LogKeyError, err)
+		return action
+	}
+	return v
+}
@@ -0,0 +1,95 @@
+package amqpx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultErrorPolicy(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Nack, DefaultErrorPolicy(io.EOF))
+	assert.Equal(t, Reject, DefaultErrorPolicy(Permanent(io.EOF)))
+	assert.Equal(t, Retry, DefaultErrorPolicy(Temporary(io.EOF)))
+	assert.Equal(t, Reject, DefaultErrorPolicy(fmt.Errorf("wrap: %w", Permanent(io.EOF))))
+
+	assert.ErrorIs(t, Permanent(io.EOF), io.EOF)
+	assert.ErrorIs(t, Temporary(io.EOF), io.EOF)
+	assert.Equal(t, io.EOF.Error(), Permanent(io.EOF).Error())
+	assert.Nil(t, Permanent(nil))
+	assert.Nil(t, Temporary(nil))
+}
+
+func TestE(t *testing.T) {
+	t.Parallel()
+
+	serve := func(policy ErrorPolicy, err error) (Action, *DeliveryRequest) {
+		fn := E(func(ctx context.Context, d *Delivery[[]byte]) error { return err })
+		fn.init(nil, policy)
+
+		req := &DeliveryRequest{in: &amqp091.Delivery{}}
+		return fn.serve(context.Background(), req), req
+	}
+
+	got, req := serve(DefaultErrorPolicy, nil)
+	assert.Equal(t, Ack, got)
+	assert.Nil(t, req.Err())
+
+	got, req = serve(DefaultErrorPolicy, Permanent(io.EOF))
+	assert.Equal(t, Reject, got)
+	assert.ErrorIs(t, req.Err(), io.EOF)
+
+	got, _ = serve(func(err error) Action { return Reject }, io.EOF)
+	assert.Equal(t, Reject, got)
+}
+
+func TestConsumer_ErrorInterceptor(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, WithLog(NoOpLogger))
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	ack := &AcknowledgerMock{
+		RejectFunc: func(tag uint64, requeue bool) error { return nil },
+	}
+	delivery := make(chan amqp091.Delivery, 1)
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	done := make(chan error, 1)
+	_, err := client.NewConsumer("foo", E(func(ctx context.Context, d *Delivery[[]byte]) error {
+		return Permanent(io.EOF)
+	}), SetConsumeInterceptor(func(next ConsumeFunc) ConsumeFunc {
+		return func(ctx context.Context, req *DeliveryRequest) Action {
+			action := next(ctx, req)
+			done <- req.Err()
+			return action
+		}
+	}), SetErrorPolicy(func(err error) Action {
+		if IsPermanent(err) {
+			return Reject
+		}
+		return Ack
+	}))
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, <-done, io.EOF)
+	for len(ack.RejectCalls()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.False(t, ack.RejectCalls()[0].Requeue)
+}
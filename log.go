@@ -1,19 +1,185 @@
 package amqpx
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
+	"time"
 )
 
+// The keys of the structured log attributes.
+const (
+	LogKeyQueue       = "queue"
+	LogKeyConsumerTag = "consumer_tag"
+	LogKeyExchange    = "exchange"
+	LogKeyRoutingKey  = "routing_key"
+	LogKeyDeliveryTag = "delivery_tag"
+	LogKeyAttempt     = "attempt"
+	LogKeyError       = "error"
+	LogKeyStack       = "stack"
+)
+
+const defaultLogRepeatInterval = time.Minute
+
 // LogFunc type is an adapter to allow the use of ordinary functions as LogFunc.
 type LogFunc func(format string, v ...any)
 
 // NoOpLogger logger does nothing
 var NoOpLogger = LogFunc(func(format string, v ...any) {})
 
-var defaultLogger = func() LogFunc {
+var defaultLogger = newLogFuncLogger(func() LogFunc {
 	l := log.New(os.Stderr, "amqpx: ", log.LstdFlags)
 	return func(format string, v ...any) {
 		l.Printf(format, v...)
 	}
-}()
+}())
+
+var discardLogger = slog.New(discardHandler{})
+
+func newLogFuncLogger(fn LogFunc) *slog.Logger {
+	return slog.New(&logFuncHandler{fn: fn})
+}
+
+// A logFuncHandler is slog.Handler writes the records as "[LEVEL] message key=value" into LogFunc.
+// The debug records are skipped.
+type logFuncHandler struct {
+	fn     LogFunc
+	attrs  string
+	prefix string
+}
+
+func (h *logFuncHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelInfo
+}
+
+func (h *logFuncHandler) Handle(_ context.Context, r slog.Record) error {
+	b := &strings.Builder{}
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(b, h.prefix, a)
+		return true
+	})
+
+	h.fn("[%s] %s%s", r.Level, r.Message, b.String())
+	return nil
+}
+
+func (h *logFuncHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	b := &strings.Builder{}
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		writeAttr(b, h.prefix, a)
+	}
+	return &logFuncHandler{fn: h.fn, attrs: b.String(), prefix: h.prefix}
+}
+
+func (h *logFuncHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &logFuncHandler{fn: h.fn, attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+func writeAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, v := range a.Value.Group() {
+			writeAttr(b, prefix, v)
+		}
+		return
+	}
+
+	if a.Value.Kind() == slog.KindString {
+		fmt.Fprintf(b, " %s%s=%q", prefix, a.Key, a.Value.String())
+		return
+	}
+	fmt.Fprintf(b, " %s%s=%v", prefix, a.Key, a.Value.Any())
+}
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// logf logs the printf message, the level is parsed from the prefix (ex: "[ERROR] ").
+func logf(l *slog.Logger, format string, v ...any) {
+	level := slog.LevelInfo
+	for _, p := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+		if prefix := "[" + p.String() + "] "; strings.HasPrefix(format, prefix) {
+			level, format = p, strings.TrimPrefix(format, prefix)
+			break
+		}
+	}
+	l.Log(context.Background(), level, fmt.Sprintf(format, v...))
+}
+
+// logReconnectError logs the error of the reconnect loop, the same errors are logged once a minute.
+func logReconnectError(l *slog.Logger, limiter *logLimiter, msg string, attempt int, err error) {
+	ok, suppressed := limiter.allow(msg + ": " + err.Error())
+	if !ok {
+		return
+	}
+
+	attrs := []any{LogKeyAttempt, attempt, LogKeyError, err}
+	if suppressed > 0 {
+		attrs = append(attrs, "suppressed", suppressed)
+	}
+	l.Error(msg, attrs...)
+}
+
+// A logLimiter suppresses the repeated messages (ex: errors of the reconnect loop).
+type logLimiter struct {
+	mx   sync.Mutex
+	seen map[string]*logLimit
+}
+
+type logLimit struct {
+	at         time.Time
+	suppressed int
+}
+
+// allow returns true if the message should be logged and the number of suppressed messages before.
+// The message is suppressed when it is repeated within a minute.
+func (l *logLimiter) allow(msg string) (bool, int) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	if l.seen == nil {
+		l.seen = make(map[string]*logLimit)
+	}
+
+	v, ok := l.seen[msg]
+	if !ok {
+		l.seen[msg] = &logLimit{at: time.Now()}
+		return true, 0
+	}
+
+	if time.Since(v.at) < defaultLogRepeatInterval {
+		v.suppressed++
+		return false, 0
+	}
+
+	suppressed := v.suppressed
+	v.at, v.suppressed = time.Now(), 0
+	return true, suppressed
+}
+
+// reset forgets the messages when the connection is restored.
+func (l *logLimiter) reset() {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+	l.seen = nil
+}
@@ -0,0 +1,93 @@
+package amqpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogFuncHandler(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	l := newLogFuncLogger(func(format string, v ...any) {
+		got = append(got, fmt.Sprintf(format, v...))
+	})
+
+	l.Debug("skipped")
+	l.With(LogKeyQueue, "foo").WithGroup("g").Error("failed", LogKeyAttempt, 2, LogKeyError, errors.New("boom"))
+	l.Warn("blocked", slog.Group("conn", "reason", "disk"))
+	assert.Equal(t, []string{
+		`[ERROR] failed queue="foo" g.attempt=2 g.error=boom`,
+		`[WARN] blocked conn.reason="disk"`,
+	}, got)
+}
+
+func TestLogf(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	l := newLogFuncLogger(func(format string, v ...any) {
+		got = append(got, fmt.Sprintf(format, v...))
+	})
+
+	logf(l, "[ERROR] amqpxgzip: read: %s", "EOF")
+	logf(l, "[WARN] slow")
+	logf(l, "no level")
+	logf(l, "[DEBUG] skipped")
+	assert.Equal(t, []string{"[ERROR] amqpxgzip: read: EOF", "[WARN] slow", "[INFO] no level"}, got)
+}
+
+func TestLogLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := logLimiter{}
+	ok, _ := l.allow("a")
+	assert.True(t, ok)
+	ok, _ = l.allow("b")
+	assert.True(t, ok)
+	ok, _ = l.allow("a")
+	assert.False(t, ok)
+	ok, _ = l.allow("a")
+	assert.False(t, ok)
+
+	// the interval is elapsed
+	l.seen["a"].at = time.Now().Add(-defaultLogRepeatInterval)
+	ok, suppressed := l.allow("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, suppressed)
+
+	l.reset()
+	ok, _ = l.allow("b")
+	assert.True(t, ok)
+}
+
+func TestDeliveryRequest_Logger(t *testing.T) {
+	t.Parallel()
+
+	assert.NotPanics(t, func() {
+		(&DeliveryRequest{}).NewFrom(nil).Logger().Error("discarded")
+	})
+}
+
+// A testLogHandler fails the test on error records.
+type testLogHandler struct {
+	t *testing.T
+}
+
+func (h testLogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.LevelError
+}
+
+func (h testLogHandler) Handle(_ context.Context, r slog.Record) error {
+	h.t.Errorf("unexpected log: %s", r.Message)
+	return nil
+}
+
+func (h testLogHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h testLogHandler) WithGroup(string) slog.Handler      { return h }
@@ -0,0 +1,60 @@
+package amqpx
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// A PanicError represents the recovered panic of the handler.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value of the panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recovered returns the error of the panic and logs it with the stack.
+func recovered(req *DeliveryRequest, v any, action Action) error {
+	err := &PanicError{Value: v, Stack: debug.Stack()}
+	req.Logger().Error("recover handler", "action", action, LogKeyError, err, LogKeyStack, string(err.Stack))
+	return err
+}
+
+// recoverConsume returns the fn which recovers the panic and returns the action,
+// the panic is available to the interceptors by DeliveryRequest.Err.
+func recoverConsume(fn ConsumeFunc, action Action) ConsumeFunc {
+	return func(ctx context.Context, req *DeliveryRequest) (status Action) {
+		defer func() {
+			if v := recover(); v != nil {
+				req.err = recovered(req, v, action)
+				status = action
+			}
+		}()
+		return fn(ctx, req)
+	}
+}
+
+// recoverBatch returns the fn which recovers the panic and returns the action for every delivery.
+func recoverBatch(fn func(context.Context, []*DeliveryRequest) []Action, action Action) func(context.Context, []*DeliveryRequest) []Action {
+	return func(ctx context.Context, reqs []*DeliveryRequest) (actions []Action) {
+		defer func() {
+			if v := recover(); v != nil {
+				actions = make([]Action, len(reqs))
+				for i, req := range reqs {
+					req.err = recovered(req, v, action)
+					actions[i] = action
+				}
+			}
+		}()
+		return fn(ctx, reqs)
+	}
+}
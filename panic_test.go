@@ -0,0 +1,126 @@
+package amqpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumer_RecoverPanic(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, WithLog(NoOpLogger))
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	acked := make(chan uint64, 1)
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error {
+			acked <- tag
+			return nil
+		},
+		NackFunc: func(tag uint64, multiple bool, requeue bool) error { return nil },
+	}
+	delivery := make(chan amqp091.Delivery, 2)
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1, Body: []byte("panic")}
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 2}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	var (
+		mx   sync.Mutex
+		errs []error
+	)
+	_, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		if string(*d.Msg) == "panic" {
+			panic(io.EOF)
+		}
+		return Ack
+	}), SetConsumeInterceptor(func(next ConsumeFunc) ConsumeFunc {
+		return func(ctx context.Context, req *DeliveryRequest) Action {
+			action := next(ctx, req)
+			mx.Lock()
+			errs = append(errs, req.Err())
+			mx.Unlock()
+			return action
+		}
+	}), SetPanicAction(Nack), SetPrefetchCount(1))
+	require.NoError(t, err)
+
+	// the semaphore is released, so the next delivery is handled
+	assert.Equal(t, uint64(2), <-acked)
+	require.Len(t, ack.NackCalls(), 1)
+	assert.Equal(t, uint64(1), ack.NackCalls()[0].Tag)
+	assert.True(t, ack.NackCalls()[0].Requeue)
+
+	mx.Lock()
+	defer mx.Unlock()
+	require.Len(t, errs, 2)
+	var panicErr *PanicError
+	require.ErrorAs(t, errs[0], &panicErr)
+	assert.ErrorIs(t, errs[0], io.EOF)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.NoError(t, errs[1])
+}
+
+func TestConsumer_RecoverInterceptorPanic(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, WithLog(NoOpLogger))
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	rejected := make(chan bool, 1)
+	ack := &AcknowledgerMock{
+		RejectFunc: func(tag uint64, requeue bool) error {
+			rejected <- requeue
+			return nil
+		},
+	}
+	delivery := make(chan amqp091.Delivery, 1)
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	_, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		return Ack
+	}), SetConsumeInterceptor(func(next ConsumeFunc) ConsumeFunc {
+		return func(ctx context.Context, req *DeliveryRequest) Action {
+			panic("interceptor")
+		}
+	}))
+	require.NoError(t, err)
+	assert.False(t, <-rejected)
+}
+
+func TestRecoverBatch(t *testing.T) {
+	t.Parallel()
+
+	fn := recoverBatch(func(ctx context.Context, reqs []*DeliveryRequest) []Action {
+		panic(errors.New("batch"))
+	}, Reject)
+
+	reqs := []*DeliveryRequest{{in: &amqp091.Delivery{}}, {in: &amqp091.Delivery{}}}
+	assert.Equal(t, []Action{Reject, Reject}, fn(context.Background(), reqs))
+	for _, req := range reqs {
+		var panicErr *PanicError
+		assert.ErrorAs(t, req.Err(), &panicErr)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"sync/atomic"
 	"time"
 
@@ -109,6 +110,12 @@ func (m *Publishing[T]) SetUserID(id string) *Publishing[T] {
 	return m
 }
 
+// SetHeader sets header.
+func (m *Publishing[T]) SetHeader(key string, value any) *Publishing[T] {
+	m.req.Headers[key] = value
+	return m
+}
+
 // SetAppID sets application id.
 func (m *Publishing[T]) SetAppID(id string) *Publishing[T] {
 	m.req.AppId = id
@@ -123,11 +130,21 @@ type Publisher[T any] struct {
 	notifyAMQPCancel chan string
 	exchange         string
 	confirm          bool
+	failOnBlocked    bool
+	blocking         *blocking
+	inflight         *inflight
 	publishExec      PublishFunc
 
 	marshaler      Marshaler
 	publishOptions publishOptions
-	log            LogFunc
+	backoff        Backoff
+	exchangeBind   []ExchangeBind
+	routingKey     *routeFunc
+	exchangeFunc   *routeFunc
+	log            *slog.Logger
+	limiter        logLimiter
+	ready          <-chan struct{}
+	connected      func() <-chan struct{}
 	done           context.Context
 	cancel         context.CancelFunc
 	err            error
@@ -138,6 +155,7 @@ func NewPublisher[T any](client *Client, exchange string, opts ...PublisherOptio
 	opt := &publisherOptions{
 		marshaler:   client.marshaler,
 		interceptor: client.wrapPublish,
+		backoff:     client.backoff,
 		publish: publishOptions{
 			ctx: context.Background(),
 		},
@@ -151,7 +169,13 @@ func NewPublisher[T any](client *Client, exchange string, opts ...PublisherOptio
 	}
 
 	if err := opt.validate(); err != nil {
-		return &Publisher[T]{err: err}
+		return &Publisher[T]{exchange: exchange, err: err}
+	}
+
+	for _, v := range []*routeFunc{opt.routingKey, opt.exchange} {
+		if v != nil && !v.accepts(new(T)) {
+			return &Publisher[T]{exchange: exchange, err: errRouteFuncType}
+		}
 	}
 
 	pub := &Publisher[T]{
@@ -169,9 +193,18 @@ func NewPublisher[T any](client *Client, exchange string, opts ...PublisherOptio
 		}(),
 		exchange:       exchange,
 		confirm:        opt.confirm,
+		failOnBlocked:  opt.failOnBlocked,
+		blocking:       client.blocking,
+		inflight:       client.publishing,
 		publishOptions: opt.publish,
 		marshaler:      opt.marshaler,
-		log:            client.logger,
+		backoff:        opt.backoff,
+		exchangeBind:   opt.exchangeBind,
+		routingKey:     opt.routingKey,
+		exchangeFunc:   opt.exchange,
+		log:            client.logger.With(LogKeyExchange, exchange),
+		ready:          client.ready,
+		connected:      client.reconnected.wait,
 	}
 	pub.done, pub.cancel = context.WithCancel(client.done)
 
@@ -190,27 +223,43 @@ func NewPublisher[T any](client *Client, exchange string, opts ...PublisherOptio
 }
 
 // Publish publishes the message.
+// The routing key and the exchange are derived from the message when the routing funcs are set,
+// the routing key of the publish options overrides them.
 func (p *Publisher[T]) Publish(m *Publishing[T], opts ...PublishOption) error {
+	m.req.opts = p.publishOptions
+	m.req.opts.exchange = p.exchange
 	if err := p.err; err != nil {
-		return p.newPublishError(m.req.opts.key, err)
+		return newPublishError(m.req.opts, err)
+	}
+
+	p.inflight.add()
+	defer p.inflight.done()
+
+	if p.routingKey != nil {
+		if key := p.routingKey.route(m.msg, m.req.Headers); key != "" {
+			m.req.opts.key = key
+		}
+	}
+	if p.exchangeFunc != nil {
+		if exchange := p.exchangeFunc.route(m.msg, m.req.Headers); exchange != "" {
+			m.req.opts.exchange = exchange
+		}
 	}
 
-	m.req.opts = p.publishOptions
 	for _, v := range opts {
 		v(&m.req.opts)
 	}
 
 	if err := m.req.opts.validate(); err != nil {
-		return p.newPublishError(m.req.opts.key, err)
+		return newPublishError(m.req.opts, err)
 	}
 
 	b, err := p.marshaler.Marshal(m.msg)
 	if err != nil {
-		return p.newPublishError(m.req.opts.key, err)
+		return newPublishError(m.req.opts, err)
 	}
 	m.req.Body = b
 	m.req.ContentType = p.marshaler.ContentType()
-	m.req.opts.exchange = p.exchange
 
 	return p.publishExec(m.req.opts.ctx, m.req)
 }
@@ -218,22 +267,30 @@ func (p *Publisher[T]) Publish(m *Publishing[T], opts ...PublishOption) error {
 func (p *Publisher[T]) publish(ctx context.Context, m *PublishingRequest) error {
 	channel := p.amqpChannel.Load()
 	if channel == nil {
-		return p.newPublishError(m.opts.key, errChannelClosed)
+		return newPublishError(m.opts, errChannelClosed)
+	}
+
+	if p.failOnBlocked && p.blocking.isBlocked() {
+		return newPublishError(m.opts, ErrConnectionBlocked)
+	}
+
+	if err := p.blocking.wait(ctx, p.done); err != nil {
+		return newPublishError(m.opts, fmt.Errorf("%w: %w", ErrConnectionBlocked, err))
 	}
 
 	confirm, err := (*channel).PublishWithDeferredConfirmWithContext(ctx, m.opts.exchange, m.opts.key, m.opts.mandatory, m.opts.immediate, m.Publishing)
 	if err != nil {
-		return p.newPublishError(m.opts.key, err)
+		return newPublishError(m.opts, err)
 	}
 
 	if confirm != nil {
 		ok, err := confirm.WaitContext(ctx)
 		if err != nil {
-			return p.newPublishError(m.opts.key, fmt.Errorf("%s: %w", errPublishConfirm, err))
+			return newPublishError(m.opts, fmt.Errorf("%s: %w", errPublishConfirm, err))
 		}
 
 		if !ok {
-			return p.newPublishError(m.opts.key, errPublishConfirm)
+			return newPublishError(m.opts, errPublishConfirm)
 		}
 	}
 	return nil
@@ -253,7 +310,7 @@ func (p *Publisher[T]) setChannel(channel Channel) {
 
 func (p *Publisher[T]) initChannel() error {
 	conn := p.conn()
-	if conn.IsClosed() {
+	if conn == nil || conn.IsClosed() {
 		return errConnClosed
 	}
 
@@ -269,6 +326,13 @@ func (p *Publisher[T]) initChannel() error {
 		}
 	}
 
+	for _, v := range p.exchangeBind {
+		if err := bindExchange(channel, v); err != nil {
+			channel.Close()
+			return err
+		}
+	}
+
 	p.setChannel(channel)
 	p.notifyAMQPClose = channel.NotifyClose(make(chan *amqp091.Error, 1))
 	p.notifyAMQPCancel = channel.NotifyCancel(make(chan string, 1))
@@ -299,31 +363,58 @@ func (p *Publisher[T]) serve() {
 }
 
 func (p *Publisher[T]) makeConnect() (exit bool) {
-	for {
-		var err error
-		if err = p.initChannel(); err == nil {
+	select {
+	case <-p.ready:
+	case <-p.done.Done():
+		return true
+	}
+
+	for attempt := 1; ; attempt++ {
+		connected := p.connected()
+		err := p.initChannel()
+		if err == nil {
+			p.limiter.reset()
 			return false
 		}
 
-		if !errors.Is(err, errConnClosed) {
-			p.log("[ERROR] exchange %q routing-key %q: %s", p.exchange, p.publishOptions.key, err)
+		// the client reconnects the connection, so the attempts are not spent
+		if errors.Is(err, errConnClosed) {
+			select {
+			case <-p.done.Done():
+				return true
+
+			case <-connected:
+			}
+			attempt--
+			continue
+		}
+
+		logReconnectError(p.log, &p.limiter, "init channel", attempt, err)
+		delay, ok := p.backoff.Next(attempt, err)
+		if !ok {
+			p.log.Error("init channel", LogKeyAttempt, attempt, LogKeyError, errMaxAttempts)
+			return true
 		}
 
 		select {
 		case <-p.done.Done():
 			return true
 
-		case <-time.After(defaultReconnectDelay):
+		case <-time.After(delay):
 		}
 	}
 }
 
 func (p *Publisher[T]) notifyReturn(channel Channel) {
 	for v := range channel.NotifyReturn(make(chan amqp091.Return, 1)) {
-		p.log("[ERROR] exchange %q routing-key %q undeliverable message desc %q \"%d\"", v.Exchange, v.RoutingKey, v.ReplyText, v.ReplyCode)
+		p.log.Error("undeliverable message",
+			LogKeyRoutingKey, v.RoutingKey,
+			slog.String("reply_text", v.ReplyText),
+			slog.Int("reply_code", int(v.ReplyCode)),
+		)
 	}
 }
 
-func (p *Publisher[T]) newPublishError(routingKey string, err error) error {
-	return fmt.Errorf("amqpx: exchange %q routing-key %q: %s", p.exchange, routingKey, err)
+func newPublishError(opts publishOptions, err error) error {
+	return fmt.Errorf("amqpx: exchange %q routing-key %q: %w", opts.exchange, opts.key, err)
 }
@@ -6,16 +6,25 @@ import "context"
 type PublisherOption func(*publisherOptions)
 
 type publisherOptions struct {
-	confirm     bool
-	publish     publishOptions
-	marshaler   Marshaler
-	interceptor []PublishInterceptor
+	confirm       bool
+	failOnBlocked bool
+	publish       publishOptions
+	marshaler     Marshaler
+	interceptor   []PublishInterceptor
+	backoff       Backoff
+	exchangeBind  []ExchangeBind
+	routingKey    *routeFunc
+	exchange      *routeFunc
 }
 
 func (p *publisherOptions) validate() error {
 	if p.marshaler == nil {
 		return errMarshalerNotFound
 	}
+
+	if p.backoff == nil {
+		p.backoff = defaultBackoff
+	}
 	return nil
 }
 
@@ -29,6 +38,14 @@ func SetConfirmMode() PublisherOption {
 	}
 }
 
+// FailOnBlocked sets failing publish with ErrConnectionBlocked when the connection is blocked by the server.
+// The default is waiting until the connection is unblocked or the publish context is done.
+func FailOnBlocked() PublisherOption {
+	return func(o *publisherOptions) {
+		o.failOnBlocked = true
+	}
+}
+
 // SetPublishInterceptor sets publish interceptor.
 func SetPublishInterceptor(i ...PublishInterceptor) PublisherOption {
 	return func(o *publisherOptions) {
@@ -45,6 +62,70 @@ func SetMarshaler(m Marshaler) PublisherOption {
 	}
 }
 
+// SetPublisherBackoff sets reconnect backoff policy of the publisher channel.
+// The default is the client policy.
+func SetPublisherBackoff(b Backoff) PublisherOption {
+	return func(o *publisherOptions) {
+		if b != nil {
+			o.backoff = b
+		}
+	}
+}
+
+// UseExchangeBind sets exchange to exchange bind, it can be used several times.
+// The binding is declared on every channel opening of the publisher.
+func UseExchangeBind(e ExchangeBind) PublisherOption {
+	return func(o *publisherOptions) {
+		o.exchangeBind = append(o.exchangeBind, e)
+	}
+}
+
+// RoutingKeyFunc derives the routing key from the message and its headers.
+// The empty result means the routing key of the publisher.
+type RoutingKeyFunc[T any] func(msg *T, headers Table) string
+
+// ExchangeFunc selects the exchange by the message and its headers.
+// The empty result means the exchange of the publisher.
+type ExchangeFunc[T any] func(msg *T, headers Table) string
+
+// A routeFunc represents the routing func of any message type.
+type routeFunc struct {
+	route   func(msg any, headers Table) string
+	accepts func(msg any) bool
+}
+
+func newRouteFunc[T any](fn func(*T, Table) string) *routeFunc {
+	return &routeFunc{
+		route: func(msg any, headers Table) string {
+			return fn(msg.(*T), headers)
+		},
+		accepts: func(msg any) bool {
+			_, ok := msg.(*T)
+			return ok
+		},
+	}
+}
+
+// UseRoutingKeyFunc sets func which derives the routing key from the message at publish time.
+// T must be the message type of the publisher.
+func UseRoutingKeyFunc[T any](fn RoutingKeyFunc[T]) PublisherOption {
+	return func(o *publisherOptions) {
+		if fn != nil {
+			o.routingKey = newRouteFunc[T](fn)
+		}
+	}
+}
+
+// UseExchangeFunc sets func which selects the exchange by the message at publish time.
+// T must be the message type of the publisher.
+func UseExchangeFunc[T any](fn ExchangeFunc[T]) PublisherOption {
+	return func(o *publisherOptions) {
+		if fn != nil {
+			o.exchange = newRouteFunc[T](fn)
+		}
+	}
+}
+
 // UseRoutingKey sets routing key.
 func UseRoutingKey(s string) PublisherOption {
 	return func(o *publisherOptions) {
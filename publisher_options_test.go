@@ -3,6 +3,7 @@ package amqpx
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -17,6 +18,8 @@ func TestPublisherOption(t *testing.T) {
 		UseRoutingKey("key"),
 		UseMandatory(true),
 		UseImmediate(true),
+		SetPublisherBackoff(ConstantBackoff{Delay: time.Second}),
+		UseExchangeBind(ExchangeBind{Source: "source", Destination: "destination"}),
 	} {
 		o(got)
 	}
@@ -28,7 +31,9 @@ func TestPublisherOption(t *testing.T) {
 			mandatory: true,
 			immediate: true,
 		},
-		marshaler: defaultBytesMarshaler,
+		marshaler:    defaultBytesMarshaler,
+		backoff:      ConstantBackoff{Delay: time.Second},
+		exchangeBind: []ExchangeBind{{Source: "source", Destination: "destination"}},
 	}
 	assert.Equal(t, want, got)
 }
@@ -3,11 +3,13 @@ package amqpx
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/rabbitmq/amqp091-go"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPublisher_Reconnect(t *testing.T) {
@@ -27,6 +29,56 @@ func TestPublisher_Reconnect(t *testing.T) {
 	<-done
 }
 
+func TestPublisher_ReconnectAttempts(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	client, mock := prep(t, UseBackoff(ConstantBackoff{Delay: time.Millisecond, MaxAttempts: 3}))
+	defer client.Close()
+
+	var closed atomic.Bool
+	mock.Conn.IsClosedFunc = func() bool {
+		return closed.Load()
+	}
+	dial := make(chan struct{})
+	mock.Dialer.DialFunc = func(_ context.Context) (Connection, error) {
+		<-dial
+		closed.Store(false)
+		return mock.Conn, nil
+	}
+
+	_ = NewPublisher[struct{}](client, ExchangeDirect, UseRoutingKey("key"))
+	opened := make(chan bool)
+	mock.Conn.ChannelFunc = func() (Channel, error) {
+		defer close(opened)
+		return channelMock(), nil
+	}
+
+	// the attempts are not spent while the client reconnects
+	closed.Store(true)
+	mock.Conn.Close()
+	time.Sleep(20 * time.Millisecond)
+	close(dial)
+	<-opened
+}
+
+func TestNewPublisher_ExchangeBind(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+
+	bound := make(chan string, 1)
+	mock.Channel.ExchangeBindFunc = func(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+		bound <- source + " " + key + " " + destination
+		return nil
+	}
+
+	_ = NewPublisher[[]byte](client, "events", UseRoutingKey("key"),
+		UseExchangeBind(ExchangeBind{Source: "events", Destination: "audit", RoutingKey: []string{"#"}}))
+	assert.Equal(t, "events # audit", <-bound)
+}
+
 func TestNewPublisher_BytesMarshaler(t *testing.T) {
 	t.Parallel()
 
@@ -59,6 +111,67 @@ func TestPublisher_Publish(t *testing.T) {
 	})
 }
 
+func TestPublisher_PublishRouting(t *testing.T) {
+	t.Parallel()
+
+	type order struct {
+		Region string
+		Status string
+	}
+
+	client, mock := prep(t)
+	defer client.Close()
+
+	type route struct{ exchange, key string }
+	published := make(chan route, 1)
+	mock.Channel.PublishWithDeferredConfirmWithContextFunc = func(ctx context.Context, exchange string, key string, mandatory bool, immediate bool, msg amqp091.Publishing) (*amqp091.DeferredConfirmation, error) {
+		published <- route{exchange: exchange, key: key}
+		return nil, nil
+	}
+
+	intercepted := make(chan route, 1)
+	pub := NewPublisher[order](client, "orders", SetMarshaler(testMarshaler),
+		UseRoutingKeyFunc(func(msg *order, headers Table) string {
+			return fmt.Sprintf("orders.%s.%s", msg.Region, msg.Status)
+		}),
+		UseExchangeFunc(func(msg *order, headers Table) string {
+			if headers["priority"] == true {
+				return "orders.priority"
+			}
+			return ""
+		}),
+		SetPublishInterceptor(func(next PublishFunc) PublishFunc {
+			return func(ctx context.Context, req *PublishingRequest) error {
+				intercepted <- route{exchange: req.Exchange(), key: req.RoutingKey()}
+				return next(ctx, req)
+			}
+		}))
+
+	require.NoError(t, pub.Publish(NewPublishing(&order{Region: "eu", Status: "created"})))
+	assert.Equal(t, route{exchange: "orders", key: "orders.eu.created"}, <-intercepted)
+	assert.Equal(t, route{exchange: "orders", key: "orders.eu.created"}, <-published)
+
+	require.NoError(t, pub.Publish(NewPublishing(&order{Region: "us", Status: "paid"}).SetHeader("priority", true)))
+	assert.Equal(t, route{exchange: "orders.priority", key: "orders.us.paid"}, <-intercepted)
+	<-published
+
+	// the routing key of the publish options overrides the func
+	require.NoError(t, pub.Publish(NewPublishing(&order{}), SetRoutingKey("manual")))
+	assert.Equal(t, route{exchange: "orders", key: "manual"}, <-intercepted)
+	<-published
+}
+
+func TestPublisher_RoutingFuncType(t *testing.T) {
+	t.Parallel()
+
+	client, _ := prep(t)
+	defer client.Close()
+
+	pub := NewPublisher[[]byte](client, "orders", UseRoutingKeyFunc(func(msg *string, headers Table) string { return *msg }))
+	b := []byte("hello")
+	assert.ErrorIs(t, pub.Publish(NewPublishing(&b)), errRouteFuncType)
+}
+
 func TestPublishing_Properties(t *testing.T) {
 	t.Parallel()
 
@@ -74,12 +187,13 @@ func TestPublishing_Properties(t *testing.T) {
 		SetTimestamp(d).
 		SetType("type_value").
 		SetUserID("user_id_value").
-		SetAppID("app_id_value")
+		SetAppID("app_id_value").
+		SetHeader("header", "header_value")
 
 	want := &Publishing[[]byte]{
 		req: &PublishingRequest{
 			Publishing: amqp091.Publishing{
-				Headers:       amqp091.Table{},
+				Headers:       amqp091.Table{"header": "header_value"},
 				DeliveryMode:  Persistent,
 				Priority:      1,
 				CorrelationId: "correlation_id_value",
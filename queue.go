@@ -2,6 +2,8 @@ package amqpx
 
 // A QueueDeclare represents a queue declaration.
 type QueueDeclare struct {
+	// Name is used by Topology, the consumer declares its queue.
+	Name       string
 	Durable    bool
 	AutoDelete bool
 	Exclusive  bool
@@ -22,8 +24,20 @@ type ExchangeDeclare struct {
 
 // A QueueBind represents a binding routing key, exchange and queue declaration.
 type QueueBind struct {
+	// Queue is used by Topology, the consumer binds its queue.
+	Queue      string
 	Exchange   string
 	RoutingKey []string
 	NoWait     bool
 	Args       Table
 }
+
+// A ExchangeBind represents a binding routing key, source and destination exchanges declaration.
+// The messages are routed from the source exchange to the destination exchange.
+type ExchangeBind struct {
+	Destination string
+	Source      string
+	RoutingKey  []string
+	NoWait      bool
+	Args        Table
+}
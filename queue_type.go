@@ -0,0 +1,308 @@
+package amqpx
+
+import (
+	"fmt"
+	"time"
+)
+
+// The queue arguments.
+const (
+	ArgQueueType              = "x-queue-type"
+	ArgDeliveryLimit          = "x-delivery-limit"
+	ArgMaxLength              = "x-max-length"
+	ArgMaxLengthBytes         = "x-max-length-bytes"
+	ArgOverflow               = "x-overflow"
+	ArgMessageTTL             = "x-message-ttl"
+	ArgExpires                = "x-expires"
+	ArgDeadLetterExchange     = "x-dead-letter-exchange"
+	ArgDeadLetterRoutingKey   = "x-dead-letter-routing-key"
+	ArgDeadLetterStrategy     = "x-dead-letter-strategy"
+	ArgMaxPriority            = "x-max-priority"
+	ArgSingleActiveConsumer   = "x-single-active-consumer"
+	ArgQuorumInitialGroupSize = "x-quorum-initial-group-size"
+	ArgInitialClusterSize     = "x-initial-cluster-size"
+	ArgMaxAge                 = "x-max-age"
+	ArgStreamMaxSegmentSize   = "x-stream-max-segment-size-bytes"
+)
+
+// The queue types.
+const (
+	QueueTypeClassic = "classic"
+	QueueTypeQuorum  = "quorum"
+	QueueTypeStream  = "stream"
+)
+
+// Overflow is the behaviour of the queue when the max length is reached.
+type Overflow string
+
+const (
+	// OverflowDropHead drops or dead-letters the oldest messages.
+	OverflowDropHead Overflow = "drop-head"
+	// OverflowRejectPublish rejects the new messages.
+	OverflowRejectPublish Overflow = "reject-publish"
+	// OverflowRejectPublishDLX rejects and dead-letters the new messages, classic queues only.
+	OverflowRejectPublishDLX Overflow = "reject-publish-dlx"
+)
+
+// DeadLetterStrategy is the dead-lettering guarantee of the quorum queue.
+type DeadLetterStrategy string
+
+const (
+	DeadLetterAtMostOnce  DeadLetterStrategy = "at-most-once"
+	DeadLetterAtLeastOnce DeadLetterStrategy = "at-least-once"
+)
+
+// A DeadLetter represents the dead-lettering of the queue.
+// The messages are dead-lettered through the default exchange when only the routing key is set.
+type DeadLetter struct {
+	Exchange string
+	// RoutingKey replaces the routing key of the dead-lettered messages, the default is the original routing key.
+	RoutingKey string
+}
+
+func (v DeadLetter) enabled() bool {
+	return v.Exchange != "" || v.RoutingKey != ""
+}
+
+// A ClassicQueue represents the classic queue arguments.
+// Zero values are not set.
+type ClassicQueue struct {
+	Name                 string
+	Durable              bool
+	AutoDelete           bool
+	Exclusive            bool
+	MaxLength            int64
+	MaxLengthBytes       int64
+	Overflow             Overflow
+	MessageTTL           time.Duration
+	Expires              time.Duration
+	DeadLetter           DeadLetter
+	MaxPriority          int
+	SingleActiveConsumer bool
+	// Args are additional arguments, they must not repeat the typed fields.
+	Args Table
+}
+
+// QueueDeclare returns the validated queue declaration.
+func (q ClassicQueue) QueueDeclare() (QueueDeclare, error) {
+	b := queueArgs{kind: "classic queue", name: q.Name, args: Table{ArgQueueType: QueueTypeClassic}}
+	b.length(q.MaxLength, q.MaxLengthBytes)
+	b.overflow(q.Overflow, OverflowDropHead, OverflowRejectPublish, OverflowRejectPublishDLX)
+	b.ttl(q.MessageTTL, q.Expires)
+	b.deadLetter(q.DeadLetter)
+	b.singleActiveConsumer(q.SingleActiveConsumer)
+
+	if q.MaxPriority != 0 {
+		if q.MaxPriority < 0 || q.MaxPriority > 255 {
+			b.fail("max priority must be between 1 and 255")
+		}
+		b.args[ArgMaxPriority] = q.MaxPriority
+	}
+
+	if q.Overflow == OverflowRejectPublishDLX && !q.DeadLetter.enabled() {
+		b.fail("overflow %q requires dead letter exchange or routing key", q.Overflow)
+	}
+
+	if err := b.merge(q.Args); err != nil {
+		return QueueDeclare{}, err
+	}
+	return QueueDeclare{Name: q.Name, Durable: q.Durable, AutoDelete: q.AutoDelete, Exclusive: q.Exclusive, Args: b.args}, nil
+}
+
+// A QuorumQueue represents the quorum queue arguments.
+// The quorum queue is always durable and can not be exclusive or auto-delete.
+// Zero values are not set.
+type QuorumQueue struct {
+	Name string
+	// DeliveryLimit is the number of the redeliveries before the message is dropped or dead-lettered.
+	DeliveryLimit      int
+	MaxLength          int64
+	MaxLengthBytes     int64
+	Overflow           Overflow
+	MessageTTL         time.Duration
+	Expires            time.Duration
+	DeadLetter         DeadLetter
+	DeadLetterStrategy DeadLetterStrategy
+	// InitialGroupSize is the number of the replicas.
+	InitialGroupSize     int
+	SingleActiveConsumer bool
+	// Args are additional arguments, they must not repeat the typed fields.
+	Args Table
+}
+
+// QueueDeclare returns the validated queue declaration.
+func (q QuorumQueue) QueueDeclare() (QueueDeclare, error) {
+	b := queueArgs{kind: "quorum queue", name: q.Name, args: Table{ArgQueueType: QueueTypeQuorum}}
+	b.length(q.MaxLength, q.MaxLengthBytes)
+	b.overflow(q.Overflow, OverflowDropHead, OverflowRejectPublish)
+	b.ttl(q.MessageTTL, q.Expires)
+	b.deadLetter(q.DeadLetter)
+	b.singleActiveConsumer(q.SingleActiveConsumer)
+	b.positive(ArgDeliveryLimit, q.DeliveryLimit)
+	b.positive(ArgQuorumInitialGroupSize, q.InitialGroupSize)
+
+	switch q.DeadLetterStrategy {
+	case "":
+	case DeadLetterAtMostOnce:
+		b.args[ArgDeadLetterStrategy] = string(q.DeadLetterStrategy)
+
+	case DeadLetterAtLeastOnce:
+		// the messages are kept until they are confirmed by the dead letter queue
+		if !q.DeadLetter.enabled() {
+			b.fail("dead letter strategy %q requires dead letter exchange or routing key", q.DeadLetterStrategy)
+		}
+		if q.Overflow != OverflowRejectPublish {
+			b.fail("dead letter strategy %q requires overflow %q", q.DeadLetterStrategy, OverflowRejectPublish)
+		}
+		b.args[ArgDeadLetterStrategy] = string(q.DeadLetterStrategy)
+
+	default:
+		b.fail("unknown dead letter strategy %q", q.DeadLetterStrategy)
+	}
+
+	if err := b.merge(q.Args); err != nil {
+		return QueueDeclare{}, err
+	}
+	return QueueDeclare{Name: q.Name, Durable: true, Args: b.args}, nil
+}
+
+// A StreamQueue represents the stream queue arguments.
+// The stream is always durable and can not be exclusive or auto-delete,
+// the messages are not removed by consuming, they are retained by the size or age.
+// Zero values are not set.
+type StreamQueue struct {
+	Name           string
+	MaxLengthBytes int64
+	// MaxAge is the retention of the messages, it is rounded down to seconds.
+	MaxAge              time.Duration
+	MaxSegmentSizeBytes int64
+	// InitialClusterSize is the number of the replicas.
+	InitialClusterSize int
+	// Args are additional arguments, they must not repeat the typed fields.
+	Args Table
+}
+
+// QueueDeclare returns the validated queue declaration.
+func (q StreamQueue) QueueDeclare() (QueueDeclare, error) {
+	b := queueArgs{kind: "stream", name: q.Name, args: Table{ArgQueueType: QueueTypeStream}}
+	b.length(0, q.MaxLengthBytes)
+	b.positive(ArgInitialClusterSize, q.InitialClusterSize)
+
+	if q.MaxSegmentSizeBytes != 0 {
+		if q.MaxSegmentSizeBytes < 0 {
+			b.fail("max segment size is negative")
+		}
+		b.args[ArgStreamMaxSegmentSize] = q.MaxSegmentSizeBytes
+	}
+
+	if q.MaxAge != 0 {
+		if q.MaxAge < time.Second {
+			b.fail("max age must be at least 1s")
+		}
+		b.args[ArgMaxAge] = fmt.Sprintf("%ds", q.MaxAge/time.Second)
+	}
+
+	if err := b.merge(q.Args); err != nil {
+		return QueueDeclare{}, err
+	}
+	return QueueDeclare{Name: q.Name, Durable: true, Args: b.args}, nil
+}
+
+// A queueArgs builds the arguments and keeps the first validation error.
+type queueArgs struct {
+	kind string
+	name string
+	args Table
+	err  error
+}
+
+func (b *queueArgs) fail(format string, v ...any) {
+	if b.err == nil {
+		b.err = fmt.Errorf("amqpx: %s %q: %s", b.kind, b.name, fmt.Sprintf(format, v...))
+	}
+}
+
+func (b *queueArgs) positive(key string, v int) {
+	if v == 0 {
+		return
+	}
+
+	if v < 0 {
+		b.fail("%s is negative", key)
+	}
+	b.args[key] = v
+}
+
+func (b *queueArgs) length(n, bytes int64) {
+	if n < 0 || bytes < 0 {
+		b.fail("max length is negative")
+	}
+
+	if n > 0 {
+		b.args[ArgMaxLength] = n
+	}
+	if bytes > 0 {
+		b.args[ArgMaxLengthBytes] = bytes
+	}
+}
+
+func (b *queueArgs) overflow(v Overflow, allowed ...Overflow) {
+	if v == "" {
+		return
+	}
+
+	for _, a := range allowed {
+		if v == a {
+			b.args[ArgOverflow] = string(v)
+			return
+		}
+	}
+	b.fail("overflow %q is not supported", v)
+}
+
+func (b *queueArgs) ttl(message, expires time.Duration) {
+	if message < 0 || expires < 0 {
+		b.fail("ttl is negative")
+	}
+
+	// the arguments are set in milliseconds
+	if (message > 0 && message < time.Millisecond) || (expires > 0 && expires < time.Millisecond) {
+		b.fail("ttl must be at least 1ms")
+	}
+
+	if message > 0 {
+		b.args[ArgMessageTTL] = message.Milliseconds()
+	}
+	if expires > 0 {
+		b.args[ArgExpires] = expires.Milliseconds()
+	}
+}
+
+func (b *queueArgs) deadLetter(v DeadLetter) {
+	if !v.enabled() {
+		return
+	}
+
+	// the empty exchange is the default exchange
+	b.args[ArgDeadLetterExchange] = v.Exchange
+	if v.RoutingKey != "" {
+		b.args[ArgDeadLetterRoutingKey] = v.RoutingKey
+	}
+}
+
+func (b *queueArgs) singleActiveConsumer(v bool) {
+	if v {
+		b.args[ArgSingleActiveConsumer] = true
+	}
+}
+
+// merge adds the additional arguments and returns the validation error.
+func (b *queueArgs) merge(args Table) error {
+	for k, v := range args {
+		if _, ok := b.args[k]; ok {
+			b.fail("argument %q is set by the field", k)
+		}
+		b.args[k] = v
+	}
+	return b.err
+}
@@ -0,0 +1,189 @@
+package amqpx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type queueDeclarer interface {
+	QueueDeclare() (QueueDeclare, error)
+}
+
+func TestQueueType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		queue queueDeclarer
+		want  QueueDeclare
+	}{
+		{
+			name: "classic",
+			queue: ClassicQueue{
+				Name:                 "foo",
+				AutoDelete:           true,
+				MaxLength:            10,
+				Overflow:             OverflowRejectPublishDLX,
+				MessageTTL:           time.Second,
+				Expires:              time.Minute,
+				DeadLetter:           DeadLetter{Exchange: "dlx", RoutingKey: "dead"},
+				MaxPriority:          5,
+				SingleActiveConsumer: true,
+				Args:                 Table{"x-queue-mode": "lazy"},
+			},
+			want: QueueDeclare{Name: "foo", AutoDelete: true, Args: Table{
+				ArgQueueType:            QueueTypeClassic,
+				ArgMaxLength:            int64(10),
+				ArgOverflow:             "reject-publish-dlx",
+				ArgMessageTTL:           int64(1000),
+				ArgExpires:              int64(60000),
+				ArgDeadLetterExchange:   "dlx",
+				ArgDeadLetterRoutingKey: "dead",
+				ArgMaxPriority:          5,
+				ArgSingleActiveConsumer: true,
+				"x-queue-mode":          "lazy",
+			}},
+		},
+		{
+			name: "quorum",
+			queue: QuorumQueue{
+				Name:               "foo",
+				DeliveryLimit:      3,
+				MaxLengthBytes:     1024,
+				Overflow:           OverflowRejectPublish,
+				DeadLetter:         DeadLetter{Exchange: "dlx"},
+				DeadLetterStrategy: DeadLetterAtLeastOnce,
+				InitialGroupSize:   3,
+			},
+			want: QueueDeclare{Name: "foo", Durable: true, Args: Table{
+				ArgQueueType:              QueueTypeQuorum,
+				ArgDeliveryLimit:          3,
+				ArgMaxLengthBytes:         int64(1024),
+				ArgOverflow:               "reject-publish",
+				ArgDeadLetterExchange:     "dlx",
+				ArgDeadLetterStrategy:     "at-least-once",
+				ArgQuorumInitialGroupSize: 3,
+			}},
+		},
+		{
+			name:  "default exchange dead letter",
+			queue: ClassicQueue{Name: "foo", DeadLetter: DeadLetter{RoutingKey: "dead"}},
+			want: QueueDeclare{Name: "foo", Args: Table{
+				ArgQueueType:            QueueTypeClassic,
+				ArgDeadLetterExchange:   "",
+				ArgDeadLetterRoutingKey: "dead",
+			}},
+		},
+		{
+			name:  "stream",
+			queue: StreamQueue{Name: "foo", MaxLengthBytes: 1 << 30, MaxAge: 7 * 24 * time.Hour, MaxSegmentSizeBytes: 1 << 20, InitialClusterSize: 3},
+			want: QueueDeclare{Name: "foo", Durable: true, Args: Table{
+				ArgQueueType:            QueueTypeStream,
+				ArgMaxLengthBytes:       int64(1 << 30),
+				ArgMaxAge:               "604800s",
+				ArgStreamMaxSegmentSize: int64(1 << 20),
+				ArgInitialClusterSize:   3,
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.queue.QueueDeclare()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQueueType_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		queue queueDeclarer
+		err   string
+	}{
+		{
+			name:  "negative length",
+			queue: ClassicQueue{Name: "foo", MaxLength: -1},
+			err:   `amqpx: classic queue "foo": max length is negative`,
+		},
+		{
+			name:  "message ttl",
+			queue: ClassicQueue{Name: "foo", MessageTTL: time.Microsecond},
+			err:   `amqpx: classic queue "foo": ttl must be at least 1ms`,
+		},
+		{
+			name:  "quorum expires",
+			queue: QuorumQueue{Name: "foo", Expires: 999 * time.Microsecond},
+			err:   `amqpx: quorum queue "foo": ttl must be at least 1ms`,
+		},
+		{
+			name:  "priority",
+			queue: ClassicQueue{Name: "foo", MaxPriority: 256},
+			err:   `amqpx: classic queue "foo": max priority must be between 1 and 255`,
+		},
+		{
+			name:  "reject publish dlx",
+			queue: ClassicQueue{Name: "foo", Overflow: OverflowRejectPublishDLX},
+			err:   `amqpx: classic queue "foo": overflow "reject-publish-dlx" requires dead letter exchange or routing key`,
+		},
+		{
+			name:  "duplicate argument",
+			queue: ClassicQueue{Name: "foo", MaxLength: 1, Args: Table{ArgMaxLength: 2}},
+			err:   `amqpx: classic queue "foo": argument "x-max-length" is set by the field`,
+		},
+		{
+			name:  "queue type argument",
+			queue: ClassicQueue{Name: "foo", Args: Table{ArgQueueType: QueueTypeQuorum}},
+			err:   `amqpx: classic queue "foo": argument "x-queue-type" is set by the field`,
+		},
+		{
+			name:  "quorum overflow",
+			queue: QuorumQueue{Name: "foo", Overflow: OverflowRejectPublishDLX},
+			err:   `amqpx: quorum queue "foo": overflow "reject-publish-dlx" is not supported`,
+		},
+		{
+			name:  "quorum delivery limit",
+			queue: QuorumQueue{Name: "foo", DeliveryLimit: -1},
+			err:   `amqpx: quorum queue "foo": x-delivery-limit is negative`,
+		},
+		{
+			name:  "at least once overflow",
+			queue: QuorumQueue{Name: "foo", DeadLetter: DeadLetter{Exchange: "dlx"}, DeadLetterStrategy: DeadLetterAtLeastOnce},
+			err:   `amqpx: quorum queue "foo": dead letter strategy "at-least-once" requires overflow "reject-publish"`,
+		},
+		{
+			name:  "at least once exchange",
+			queue: QuorumQueue{Name: "foo", Overflow: OverflowRejectPublish, DeadLetterStrategy: DeadLetterAtLeastOnce},
+			err:   `amqpx: quorum queue "foo": dead letter strategy "at-least-once" requires dead letter exchange or routing key`,
+		},
+		{
+			name:  "unknown strategy",
+			queue: QuorumQueue{Name: "foo", DeadLetterStrategy: "once"},
+			err:   `amqpx: quorum queue "foo": unknown dead letter strategy "once"`,
+		},
+		{
+			name:  "stream max age",
+			queue: StreamQueue{Name: "foo", MaxAge: time.Millisecond},
+			err:   `amqpx: stream "foo": max age must be at least 1s`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tt.queue.QueueDeclare()
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
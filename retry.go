@@ -0,0 +1,178 @@
+package amqpx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// HeaderRetryAttempt is the header of the retry attempt of the message.
+const HeaderRetryAttempt = "x-retry-attempt"
+
+// A RetryPolicy represents the delayed retry of the deliveries which are handled with the Retry action.
+//
+// The copy of the delivery is published to the retry queue "<queue>.retry.<delay>" with the message TTL,
+// the expired message is dead-lettered back to the origin queue. The delivery is routed
+// to the parking lot queue when the attempts are exceeded.
+type RetryPolicy struct {
+	// Delays is the backoff schedule of the attempts, the last delay is used for the rest attempts.
+	Delays []time.Duration
+	// MaxAttempts is the number of the retries, the default is the number of the delays.
+	MaxAttempts int
+	// ParkingLot is the queue of the deliveries which exceeded the attempts, the default is "<queue>.parking-lot".
+	ParkingLot string
+}
+
+func (p RetryPolicy) validate() error {
+	if len(p.Delays) == 0 || p.MaxAttempts < 0 {
+		return errRetryPolicy
+	}
+
+	for _, d := range p.Delays {
+		if d <= 0 {
+			return errRetryPolicy
+		}
+	}
+	return nil
+}
+
+// A retrier routes the retried deliveries of the queue.
+type retrier struct {
+	queue       string
+	delays      []time.Duration
+	maxAttempts int
+	parkingLot  string
+}
+
+func newRetrier(queue string, p RetryPolicy) *retrier {
+	r := &retrier{
+		queue:       queue,
+		delays:      p.Delays,
+		maxAttempts: p.MaxAttempts,
+		parkingLot:  p.ParkingLot,
+	}
+
+	if r.maxAttempts == 0 {
+		r.maxAttempts = len(r.delays)
+	}
+	if r.parkingLot == "" {
+		r.parkingLot = queue + ".parking-lot"
+	}
+	return r
+}
+
+func (r *retrier) retryQueue(delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%s", r.queue, delay)
+}
+
+// declare declares the retry queues and the parking lot.
+func (r *retrier) declare(channel Channel) error {
+	seen := make(map[time.Duration]bool, len(r.delays))
+	for _, d := range r.delays {
+		if seen[d] {
+			continue
+		}
+		seen[d] = true
+
+		if _, err := channel.QueueDeclare(r.retryQueue(d), true, false, false, false, amqp091.Table{
+			ArgMessageTTL:           d.Milliseconds(),
+			ArgDeadLetterExchange:   "",
+			ArgDeadLetterRoutingKey: r.queue,
+		}); err != nil {
+			return fmt.Errorf("declare retry queue: %w", err)
+		}
+	}
+
+	if _, err := channel.QueueDeclare(r.parkingLot, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare parking lot: %w", err)
+	}
+	return nil
+}
+
+// route returns the queue of the attempt.
+func (r *retrier) route(attempt int) (queue string, parked bool) {
+	if attempt > r.maxAttempts {
+		return r.parkingLot, true
+	}
+	return r.retryQueue(r.delays[min(attempt, len(r.delays))-1]), false
+}
+
+// publish publishes the copy of the delivery to the retry queue or the parking lot.
+func (r *retrier) publish(ctx context.Context, channel Channel, d *amqp091.Delivery) (attempt int, err error) {
+	attempt = retryAttempt(d.Headers) + 1
+	queue, parked := r.route(attempt)
+
+	headers := make(amqp091.Table, len(d.Headers)+1)
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	if !parked {
+		headers[HeaderRetryAttempt] = int64(attempt)
+	}
+	return attempt, publishCopy(ctx, channel, queue, d, headers)
+}
+
+// publishCopy publishes the copy of the delivery to the queue through the default exchange
+// and waits the confirmation.
+func publishCopy(ctx context.Context, channel Channel, queue string, d *amqp091.Delivery, headers amqp091.Table) error {
+	// the user id is omitted, it must match the user of the connection
+	confirm, err := channel.PublishWithDeferredConfirmWithContext(ctx, "", queue, false, false, amqp091.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    d.DeliveryMode,
+		Priority:        d.Priority,
+		CorrelationId:   d.CorrelationId,
+		ReplyTo:         d.ReplyTo,
+		Expiration:      d.Expiration,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Type:            d.Type,
+		AppId:           d.AppId,
+		Body:            d.Body,
+	})
+	if err != nil {
+		return err
+	}
+
+	if confirm != nil {
+		ok, err := confirm.WaitContext(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %w", errPublishConfirm, err)
+		}
+
+		if !ok {
+			return errPublishConfirm
+		}
+	}
+	return nil
+}
+
+func retryAttempt(headers amqp091.Table) int {
+	return int(headerInt(headers, HeaderRetryAttempt))
+}
+
+// RetryAttempt returns the number of the retries of the message.
+func (d *DeliveryRequest) RetryAttempt() int {
+	return retryAttempt(d.in.Headers)
+}
+
+// retryDelivery publishes the copy of the delivery for the retry,
+// it returns the action which settles the original delivery.
+func (c *consumer) retryDelivery(ctx context.Context, channel Channel, d *amqp091.Delivery, req *DeliveryRequest) Action {
+	if c.retry == nil {
+		req.Logger().Error("requeue delivery", LogKeyError, errRetryPolicyNotSet)
+		return Nack
+	}
+
+	attempt, err := c.retry.publish(ctx, channel, d)
+	if err != nil {
+		req.Logger().Error("requeue delivery", LogKeyAttempt, attempt, LogKeyError, fmt.Errorf("retry: %w", err))
+		return Nack
+	}
+
+	req.Logger().Debug("retry delivery", LogKeyAttempt, attempt)
+	return Ack
+}
@@ -0,0 +1,126 @@
+package amqpx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrier_route(t *testing.T) {
+	t.Parallel()
+
+	r := newRetrier("foo", RetryPolicy{Delays: []time.Duration{time.Second, time.Minute}, MaxAttempts: 3})
+	for attempt, want := range map[int]string{1: "foo.retry.1s", 2: "foo.retry.1m0s", 3: "foo.retry.1m0s"} {
+		got, parked := r.route(attempt)
+		assert.Equal(t, want, got)
+		assert.False(t, parked)
+	}
+
+	got, parked := r.route(4)
+	assert.Equal(t, "foo.parking-lot", got)
+	assert.True(t, parked)
+
+	r = newRetrier("foo", RetryPolicy{Delays: []time.Duration{time.Second}, ParkingLot: "bar"})
+	got, parked = r.route(2)
+	assert.Equal(t, "bar", got)
+	assert.True(t, parked)
+}
+
+func TestRetry_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Retry", Retry.String())
+}
+
+func TestConsumer_Retry(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t)
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error { return nil },
+	}
+	delivery := make(chan amqp091.Delivery, 2)
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1, Body: []byte("first"), UserId: "guest"}
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 2, Body: []byte("last"), Headers: amqp091.Table{HeaderRetryAttempt: int32(2)}}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+	mock.Channel.QueueDeclareFunc = func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+		return amqp091.Queue{}, nil
+	}
+	mock.Channel.ConfirmFunc = func(noWait bool) error {
+		return nil
+	}
+	mock.Channel.PublishWithDeferredConfirmWithContextFunc = func(ctx context.Context, exchange string, key string, mandatory bool, immediate bool, msg amqp091.Publishing) (*amqp091.DeferredConfirmation, error) {
+		return nil, nil
+	}
+
+	cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		return Retry
+	}), SetRetryPolicy(RetryPolicy{Delays: []time.Duration{time.Second, time.Minute}}), SetPrefetchCount(1))
+	require.NoError(t, err)
+	for cons.Stats().Retried != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	require.NoError(t, cons.Cancel(context.Background()))
+
+	declared := mock.Channel.QueueDeclareCalls()
+	require.Len(t, declared, 3)
+	assert.Equal(t, "foo.retry.1s", declared[0].Name)
+	assert.Equal(t, amqp091.Table{ArgMessageTTL: int64(1000), ArgDeadLetterExchange: "", ArgDeadLetterRoutingKey: "foo"}, declared[0].Args)
+	assert.Equal(t, "foo.retry.1m0s", declared[1].Name)
+	assert.Equal(t, "foo.parking-lot", declared[2].Name)
+	assert.True(t, declared[2].Durable)
+
+	published := mock.Channel.PublishWithDeferredConfirmWithContextCalls()
+	require.Len(t, published, 2)
+	assert.Equal(t, "", published[0].Exchange)
+	assert.Equal(t, "foo.retry.1s", published[0].Key)
+	assert.Equal(t, amqp091.Publishing{Headers: amqp091.Table{HeaderRetryAttempt: int64(1)}, Body: []byte("first")}, published[0].Msg)
+
+	// the attempts are exceeded
+	assert.Equal(t, "foo.parking-lot", published[1].Key)
+	assert.Equal(t, amqp091.Table{HeaderRetryAttempt: int32(2)}, published[1].Msg.Headers)
+
+	assert.Len(t, ack.AckCalls(), 2)
+	assert.Equal(t, uint64(0), cons.Stats().Acked)
+}
+
+func TestConsumer_RetryPolicyNotSet(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, WithLog(NoOpLogger))
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	done := make(chan bool)
+	ack := &AcknowledgerMock{
+		NackFunc: func(tag uint64, multiple bool, requeue bool) error {
+			close(done)
+			return nil
+		},
+	}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		ch := make(chan amqp091.Delivery, 1)
+		ch <- amqp091.Delivery{Acknowledger: ack}
+		return ch, nil
+	}
+
+	_, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		return Retry
+	}))
+	require.NoError(t, err)
+	<-done
+	assert.True(t, ack.NackCalls()[0].Requeue)
+}
@@ -0,0 +1,23 @@
+package amqpx
+
+//go:generate ./bin/stringer -type=State
+
+// State represents the state of the client connection.
+type State int32
+
+const (
+	// StateConnecting means the client is dialing the first connection.
+	StateConnecting State = iota
+
+	// StateConnected means the connection is open.
+	StateConnected
+
+	// StateReconnecting means the connection is lost and the client is dialing a new one.
+	StateReconnecting
+
+	// StateClosed means the client is closed.
+	StateClosed
+
+	// StateFailed means the connection is permanently lost, Client.Err returns the cause.
+	StateFailed
+)
@@ -0,0 +1,146 @@
+package amqpx
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+)
+
+// ArgStreamOffset is the consume argument and the delivery header of the stream offset.
+const ArgStreamOffset = "x-stream-offset"
+
+// A StreamOffset represents the starting point of the stream consumer.
+type StreamOffset struct {
+	value any
+}
+
+var (
+	// StreamOffsetFirst starts from the first available message of the stream.
+	StreamOffsetFirst = StreamOffset{value: "first"}
+	// StreamOffsetLast starts from the last written chunk of messages.
+	StreamOffsetLast = StreamOffset{value: "last"}
+	// StreamOffsetNext starts from the next message written after the consumer is started.
+	StreamOffsetNext = StreamOffset{value: "next"}
+)
+
+// StreamOffsetAt starts from the offset of the message.
+func StreamOffsetAt(offset int64) StreamOffset {
+	return StreamOffset{value: offset}
+}
+
+// StreamOffsetTimestamp starts from the messages written after t, the precision is a second.
+func StreamOffsetTimestamp(t time.Time) StreamOffset {
+	return StreamOffset{value: t}
+}
+
+// StreamOffsetInterval starts from the messages written during the interval before now (ex: last hour).
+func StreamOffsetInterval(d time.Duration) StreamOffset {
+	return StreamOffset{value: fmt.Sprintf("%ds", d/time.Second)}
+}
+
+// String returns the value of x-stream-offset argument.
+func (o StreamOffset) String() string {
+	if t, ok := o.value.(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(o.value)
+}
+
+func streamOffset(headers amqp091.Table) (int64, bool) {
+	switch v := headers[ArgStreamOffset].(type) {
+	case int64:
+		return v, true
+
+	case int32:
+		return int64(v), true
+
+	case int:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// A streamCursor tracks the processed offsets to resume the stream consumer on reconnect.
+//
+// The handlers finish out of order, so the consumer resumes from the lowest offset
+// which is not processed yet, the processed offsets above it are redelivered.
+type streamCursor struct {
+	start StreamOffset
+
+	mx        sync.Mutex
+	last      int64
+	processed bool
+	// pending counts the started offsets, the offset is redelivered after reconnect
+	// while the handler of the lost channel is running
+	pending map[int64]int
+}
+
+// offset returns the lowest pending offset, the offset after the last processed message or the starting point.
+func (s *streamCursor) offset() StreamOffset {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if len(s.pending) != 0 {
+		lowest, first := int64(0), true
+		for offset := range s.pending {
+			if first || offset < lowest {
+				lowest, first = offset, false
+			}
+		}
+		return StreamOffsetAt(lowest)
+	}
+
+	if !s.processed {
+		return s.start
+	}
+	return StreamOffsetAt(s.last + 1)
+}
+
+// begin marks the offset of the delivery as pending.
+func (s *streamCursor) begin(d *amqp091.Delivery) {
+	if s == nil {
+		return
+	}
+
+	offset, ok := streamOffset(d.Headers)
+	if !ok {
+		return
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if s.pending == nil {
+		s.pending = make(map[int64]int)
+	}
+	s.pending[offset]++
+}
+
+// done marks the pending offset of the delivery as processed or released if the delivery is not handled.
+func (s *streamCursor) done(d *amqp091.Delivery, processed bool) {
+	if s == nil {
+		return
+	}
+
+	offset, ok := streamOffset(d.Headers)
+	if !ok {
+		return
+	}
+
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	if n, ok := s.pending[offset]; ok {
+		if n > 1 {
+			s.pending[offset] = n - 1
+		} else {
+			delete(s.pending, offset)
+		}
+	}
+
+	if processed && (!s.processed || offset > s.last) {
+		s.last, s.processed = offset, true
+	}
+}
@@ -0,0 +1,137 @@
+package amqpx
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamOffset(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "first", StreamOffsetFirst.String())
+	assert.Equal(t, "last", StreamOffsetLast.String())
+	assert.Equal(t, "next", StreamOffsetNext.String())
+	assert.Equal(t, "42", StreamOffsetAt(42).String())
+	assert.Equal(t, "3600s", StreamOffsetInterval(time.Hour).String())
+	assert.Equal(t, "2023-01-02T03:04:05Z", StreamOffsetTimestamp(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)).String())
+}
+
+func TestStreamCursor(t *testing.T) {
+	t.Parallel()
+
+	delivery := func(offset int64) *amqp091.Delivery {
+		return &amqp091.Delivery{Headers: amqp091.Table{ArgStreamOffset: offset}}
+	}
+
+	c := &streamCursor{start: StreamOffsetFirst}
+	assert.Equal(t, StreamOffsetFirst, c.offset())
+
+	for offset := int64(3); offset <= 6; offset++ {
+		c.begin(delivery(offset))
+	}
+	assert.Equal(t, StreamOffsetAt(3), c.offset())
+
+	// the handlers finish out of order
+	c.done(delivery(5), true)
+	c.done(delivery(3), true)
+	assert.Equal(t, StreamOffsetAt(4), c.offset())
+
+	// the released offset is not processed
+	c.done(delivery(4), false)
+	assert.Equal(t, StreamOffsetAt(6), c.offset())
+
+	c.done(delivery(6), true)
+	assert.Equal(t, StreamOffsetAt(7), c.offset())
+}
+
+func TestConsumer_Stream(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	client, mock := prep(t)
+	defer client.Close()
+
+	var calls atomic.Int32
+	acked := make(chan bool)
+	consumed := make(chan amqp091.Table, 2)
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		ch := make(chan amqp091.Delivery, 1)
+		if calls.Add(1) == 1 {
+			ch <- amqp091.Delivery{
+				Headers: amqp091.Table{ArgStreamOffset: int64(10)},
+				Acknowledger: &AcknowledgerMock{
+					AckFunc: func(tag uint64, multiple bool) error {
+						close(acked)
+						return nil
+					},
+				},
+			}
+		}
+		consumed <- args
+		return ch, nil
+	}
+
+	var offset int64
+	_, err := client.NewConsumer("stream", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		offset, _ = d.Req.StreamOffset()
+		return Ack
+	}), SetStreamOffset(StreamOffsetFirst), SetPrefetchCount(10), SetConsumerArgs(Table{ArgStreamOffset: "last", "x-custom": 1}))
+	require.NoError(t, err)
+	assert.Equal(t, amqp091.Table{ArgStreamOffset: "first", "x-custom": 1}, <-consumed)
+	<-acked
+	assert.Equal(t, int64(10), offset)
+
+	// the consumer resumes after the last processed offset
+	mock.Channel.Close()
+	assert.Equal(t, amqp091.Table{ArgStreamOffset: int64(11), "x-custom": 1}, <-consumed)
+}
+
+func TestConsumer_StreamOutOfOrder(t *testing.T) {
+	t.Parallel()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	client, mock := prep(t)
+	defer client.Close()
+
+	var calls atomic.Int32
+	acked := make(chan uint64, 2)
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error {
+			acked <- tag
+			return nil
+		},
+	}
+	consumed := make(chan amqp091.Table, 2)
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		ch := make(chan amqp091.Delivery, 2)
+		if calls.Add(1) == 1 {
+			ch <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1, Headers: amqp091.Table{ArgStreamOffset: int64(10)}}
+			ch <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 2, Headers: amqp091.Table{ArgStreamOffset: int64(11)}}
+		}
+		consumed <- args
+		return ch, nil
+	}
+
+	release := make(chan struct{})
+	_, err := client.NewConsumer("stream", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		if offset, _ := d.Req.StreamOffset(); offset == 10 {
+			<-release
+		}
+		return Ack
+	}), SetStreamOffset(StreamOffsetFirst), SetPrefetchCount(2))
+	require.NoError(t, err)
+	assert.Equal(t, amqp091.Table{ArgStreamOffset: "first"}, <-consumed)
+	assert.Equal(t, uint64(2), <-acked)
+
+	// the consumer resumes from the offset which is still handled
+	mock.Channel.Close()
+	assert.Equal(t, amqp091.Table{ArgStreamOffset: int64(10)}, <-consumed)
+	close(release)
+	assert.Equal(t, uint64(1), <-acked)
+}
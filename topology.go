@@ -0,0 +1,156 @@
+package amqpx
+
+import (
+	"fmt"
+)
+
+// A Topology represents the exchanges, queues and bindings which are declared on connect
+// and re-declared on every reconnect before the consumers and publishers resume,
+// so auto-delete and non-durable resources survive the broker restarts.
+//
+// The exchanges are declared first, then the queues, the exchange bindings and the queue bindings.
+type Topology struct {
+	Exchanges        []ExchangeDeclare
+	Queues           []QueueDeclare
+	ExchangeBindings []ExchangeBind
+	QueueBindings    []QueueBind
+}
+
+func (t Topology) isEmpty() bool {
+	return len(t.Exchanges) == 0 && len(t.Queues) == 0 && len(t.ExchangeBindings) == 0 && len(t.QueueBindings) == 0
+}
+
+func (t Topology) merge(v Topology) Topology {
+	return Topology{
+		Exchanges:        append(t.Exchanges, v.Exchanges...),
+		Queues:           append(t.Queues, v.Queues...),
+		ExchangeBindings: append(t.ExchangeBindings, v.ExchangeBindings...),
+		QueueBindings:    append(t.QueueBindings, v.QueueBindings...),
+	}
+}
+
+func (t Topology) validate() error {
+	for _, v := range t.Exchanges {
+		if v.Name == "" {
+			return fmt.Errorf("exchange: name is empty")
+		}
+		if v.Type == "" {
+			return fmt.Errorf("exchange %q: type is empty", v.Name)
+		}
+	}
+
+	for _, v := range t.ExchangeBindings {
+		if v.Source == "" || v.Destination == "" {
+			return fmt.Errorf("exchange bind %q -> %q: exchange is empty", v.Source, v.Destination)
+		}
+	}
+
+	for _, v := range t.QueueBindings {
+		if v.Queue == "" {
+			return fmt.Errorf("queue bind: queue is empty")
+		}
+		if v.Exchange == "" {
+			return fmt.Errorf("queue bind %q: exchange is empty", v.Queue)
+		}
+	}
+	return nil
+}
+
+// declare declares the topology on a new channel of the connection.
+func (t Topology) declare(conn Connection) error {
+	if t.isEmpty() {
+		return nil
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("topology: create channel: %w", err)
+	}
+	defer channel.Close()
+
+	for _, v := range t.Exchanges {
+		if err := channel.ExchangeDeclare(v.Name, v.Type, v.Durable, v.AutoDelete, v.Internal, v.NoWait, v.Args); err != nil {
+			return fmt.Errorf("topology: declare exchange %q: %w", v.Name, err)
+		}
+	}
+
+	for _, v := range t.Queues {
+		if _, err := channel.QueueDeclare(v.Name, v.Durable, v.AutoDelete, v.Exclusive, v.NoWait, v.Args); err != nil {
+			return fmt.Errorf("topology: declare queue %q: %w", v.Name, err)
+		}
+	}
+
+	for _, v := range t.ExchangeBindings {
+		if err := bindExchange(channel, v); err != nil {
+			return fmt.Errorf("topology: %w", err)
+		}
+	}
+
+	for _, v := range t.QueueBindings {
+		for _, k := range v.RoutingKey {
+			if err := channel.QueueBind(v.Queue, k, v.Exchange, v.NoWait, v.Args); err != nil {
+				return fmt.Errorf("topology: bind queue %q: %w", v.Queue, err)
+			}
+		}
+	}
+	return nil
+}
+
+// unbindQueue removes the routing keys of the queue binding, so it is not re-declared on reconnect.
+func (t Topology) unbindQueue(b QueueBind) Topology {
+	bindings := make([]QueueBind, 0, len(t.QueueBindings))
+	for _, v := range t.QueueBindings {
+		if v.Queue == b.Queue && v.Exchange == b.Exchange {
+			if v.RoutingKey = exceptKeys(v.RoutingKey, b.RoutingKey); len(v.RoutingKey) == 0 {
+				continue
+			}
+		}
+		bindings = append(bindings, v)
+	}
+
+	t.QueueBindings = bindings
+	return t
+}
+
+// unbindExchange removes the routing keys of the exchange binding, so it is not re-declared on reconnect.
+func (t Topology) unbindExchange(b ExchangeBind) Topology {
+	bindings := make([]ExchangeBind, 0, len(t.ExchangeBindings))
+	for _, v := range t.ExchangeBindings {
+		if v.Source == b.Source && v.Destination == b.Destination {
+			if v.RoutingKey = exceptKeys(v.RoutingKey, b.RoutingKey); len(v.RoutingKey) == 0 {
+				continue
+			}
+		}
+		bindings = append(bindings, v)
+	}
+
+	t.ExchangeBindings = bindings
+	return t
+}
+
+func exceptKeys(keys, except []string) []string {
+	var out []string
+	for _, k := range keys {
+		found := false
+		for _, e := range except {
+			if k == e {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func bindExchange(channel Channel, v ExchangeBind) error {
+	for _, k := range v.RoutingKey {
+		if err := channel.ExchangeBind(v.Destination, k, v.Source, v.NoWait, v.Args); err != nil {
+			return fmt.Errorf("bind exchange %q -> %q: %w", v.Source, v.Destination, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package amqpx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopology_Declare(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	channel := &ChannelMock{
+		ExchangeDeclareFunc: func(name string, kind string, durable bool, autoDelete bool, internal bool, noWait bool, args amqp091.Table) error {
+			got = append(got, fmt.Sprintf("exchange %s %s", name, kind))
+			return nil
+		},
+		QueueDeclareFunc: func(name string, durable bool, autoDelete bool, exclusive bool, noWait bool, args amqp091.Table) (amqp091.Queue, error) {
+			got = append(got, fmt.Sprintf("queue %s", name))
+			return amqp091.Queue{}, nil
+		},
+		ExchangeBindFunc: func(destination string, key string, source string, noWait bool, args amqp091.Table) error {
+			got = append(got, fmt.Sprintf("exchange bind %s %s %s", source, key, destination))
+			return nil
+		},
+		QueueBindFunc: func(name string, key string, exchange string, noWait bool, args amqp091.Table) error {
+			got = append(got, fmt.Sprintf("queue bind %s %s %s", exchange, key, name))
+			return nil
+		},
+		CloseFunc: func() error {
+			got = append(got, "close")
+			return nil
+		},
+	}
+	conn := &ConnectionMock{
+		ChannelFunc: func() (Channel, error) {
+			return channel, nil
+		},
+	}
+
+	// the declarations are ordered by dependencies
+	err := Topology{
+		QueueBindings:    []QueueBind{{Queue: "orders", Exchange: "events", RoutingKey: []string{"order.*"}}},
+		ExchangeBindings: []ExchangeBind{{Source: "root", Destination: "events", RoutingKey: []string{"a", "b"}}},
+		Queues:           []QueueDeclare{{Name: "orders"}},
+		Exchanges:        []ExchangeDeclare{{Name: "root", Type: "topic"}, {Name: "events", Type: "topic"}},
+	}.declare(conn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"exchange root topic",
+		"exchange events topic",
+		"queue orders",
+		"exchange bind root a events",
+		"exchange bind root b events",
+		"queue bind events order.* orders",
+		"close",
+	}, got)
+}
+
+func TestTopology_DeclareEmpty(t *testing.T) {
+	t.Parallel()
+
+	// no channel is opened
+	assert.NoError(t, Topology{}.declare(&ConnectionMock{}))
+}
+
+func TestTopology_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		topology Topology
+		err      string
+	}{
+		{name: "exchange name", topology: Topology{Exchanges: []ExchangeDeclare{{Type: "direct"}}}, err: "exchange: name is empty"},
+		{name: "exchange type", topology: Topology{Exchanges: []ExchangeDeclare{{Name: "foo"}}}, err: `exchange "foo": type is empty`},
+		{name: "exchange bind", topology: Topology{ExchangeBindings: []ExchangeBind{{Source: "foo"}}}, err: `exchange bind "foo" -> "": exchange is empty`},
+		{name: "queue bind queue", topology: Topology{QueueBindings: []QueueBind{{Exchange: "foo"}}}, err: "queue bind: queue is empty"},
+		{name: "queue bind exchange", topology: Topology{QueueBindings: []QueueBind{{Queue: "foo"}}}, err: `queue bind "foo": exchange is empty`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.EqualError(t, tt.topology.validate(), tt.err)
+		})
+	}
+}
+
+func TestTopology_Unbind(t *testing.T) {
+	t.Parallel()
+
+	topology := Topology{
+		QueueBindings: []QueueBind{
+			{Queue: "foo", Exchange: "events", RoutingKey: []string{"a", "b"}},
+			{Queue: "bar", Exchange: "events", RoutingKey: []string{"a"}},
+		},
+		ExchangeBindings: []ExchangeBind{
+			{Source: "root", Destination: "events", RoutingKey: []string{"a", "b"}},
+			{Source: "root", Destination: "orders", RoutingKey: []string{"a"}},
+		},
+	}
+
+	got := topology.unbindQueue(QueueBind{Queue: "foo", Exchange: "events", RoutingKey: []string{"a"}})
+	got = got.unbindQueue(QueueBind{Queue: "bar", Exchange: "events", RoutingKey: []string{"a"}})
+	got = got.unbindExchange(ExchangeBind{Source: "root", Destination: "events", RoutingKey: []string{"a", "b"}})
+	assert.Equal(t, Topology{
+		QueueBindings:    []QueueBind{{Queue: "foo", Exchange: "events", RoutingKey: []string{"b"}}},
+		ExchangeBindings: []ExchangeBind{{Source: "root", Destination: "orders", RoutingKey: []string{"a"}}},
+	}, got)
+
+	// the topology is not changed
+	assert.Len(t, topology.QueueBindings, 2)
+	assert.Equal(t, []string{"a", "b"}, topology.QueueBindings[0].RoutingKey)
+}
@@ -0,0 +1,77 @@
+package amqpx
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// A watchdog limits the duration of the handlers and warns about the slow handlers.
+type watchdog struct {
+	timeout time.Duration
+	action  Action
+	slow    time.Duration
+	stats   *consumerStats
+}
+
+// watch runs the handler with the timeout, the result of timedOut is returned when the timeout is passed.
+// The handler is abandoned then, it keeps running in the background with the done context
+// and its result is ignored, so the handler must not use the delivery after the context is done.
+// The abandoned handler is counted until it returns.
+func watch[R any](w *watchdog, ctx context.Context, log *slog.Logger, fn func(context.Context) R, timedOut func() R) R {
+	if w == nil {
+		return fn(ctx)
+	}
+
+	if w.slow > 0 {
+		start := time.Now()
+		warn := time.AfterFunc(w.slow, func() {
+			w.stats.slow.Add(1)
+			log.Warn("slow handler", "elapsed", time.Since(start).Round(time.Millisecond))
+		})
+		defer warn.Stop()
+	}
+
+	if w.timeout <= 0 {
+		return fn(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	// the state is changed once: the handler is either finished or abandoned
+	const (
+		running = iota
+		finished
+		abandoned
+	)
+	var state atomic.Int32
+	result := make(chan R, 1)
+	go func() {
+		result <- fn(ctx)
+		if !state.CompareAndSwap(running, finished) {
+			w.stats.abandoned.Add(-1)
+		}
+	}()
+
+	// the context of the delivery can be done before, e.g. on reconnect,
+	// so the handler is abandoned only by its own timer
+	timer := time.NewTimer(w.timeout)
+	defer timer.Stop()
+
+	select {
+	case r := <-result:
+		return r
+
+	case <-timer.C:
+		if !state.CompareAndSwap(running, abandoned) {
+			return <-result
+		}
+
+		w.stats.abandoned.Add(1)
+		w.stats.timedOut.Add(1)
+		log.Error("handler timeout", "action", w.action, "timeout", w.timeout, LogKeyError, context.DeadlineExceeded)
+		return timedOut()
+	}
+}
@@ -0,0 +1,108 @@
+package amqpx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("slow handler", func(t *testing.T) {
+		t.Parallel()
+
+		w := &watchdog{slow: time.Millisecond, stats: &consumerStats{}}
+		got := watch(w, context.Background(), discardLogger, func(ctx context.Context) Action {
+			time.Sleep(20 * time.Millisecond)
+			return Ack
+		}, func() Action { return Nack })
+		assert.Equal(t, Ack, got)
+		assert.Equal(t, uint64(1), w.stats.slow.Load())
+		assert.Equal(t, uint64(0), w.stats.timedOut.Load())
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		t.Parallel()
+
+		w := &watchdog{timeout: time.Hour, stats: &consumerStats{}}
+		got := watch(w, context.Background(), discardLogger, func(ctx context.Context) Action {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok)
+			return Reject
+		}, func() Action { return Nack })
+		assert.Equal(t, Reject, got)
+	})
+
+	t.Run("abandoned", func(t *testing.T) {
+		t.Parallel()
+
+		w := &watchdog{timeout: time.Millisecond, action: Nack, stats: &consumerStats{}}
+		release := make(chan struct{})
+		returned := make(chan struct{})
+		got := watch(w, context.Background(), discardLogger, func(ctx context.Context) Action {
+			defer close(returned)
+			<-release
+			return Ack
+		}, func() Action { return Nack })
+		assert.Equal(t, Nack, got)
+		assert.Equal(t, 1, w.stats.load().Abandoned)
+
+		// the handler is counted until it returns
+		close(release)
+		<-returned
+		assert.Eventually(t, func() bool { return w.stats.load().Abandoned == 0 }, time.Second, time.Millisecond)
+		assert.Equal(t, uint64(1), w.stats.timedOut.Load())
+	})
+}
+
+func TestConsumer_HandlerTimeout(t *testing.T) {
+	t.Parallel()
+
+	client, mock := prep(t, WithLog(NoOpLogger))
+	defer client.Close()
+	defer time.AfterFunc(defaultTimeout, func() { panic("deadlock") }).Stop()
+
+	acked := make(chan uint64, 1)
+	ack := &AcknowledgerMock{
+		AckFunc: func(tag uint64, multiple bool) error {
+			acked <- tag
+			return nil
+		},
+		RejectFunc: func(tag uint64, requeue bool) error { return nil },
+	}
+	delivery := make(chan amqp091.Delivery, 2)
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 1, Body: []byte("stuck")}
+	delivery <- amqp091.Delivery{Acknowledger: ack, DeliveryTag: 2}
+	mock.Channel.ConsumeFunc = func(queue string, consumer string, autoAck bool, exclusive bool, noLocal bool, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error) {
+		return delivery, nil
+	}
+	mock.Channel.CancelFunc = func(consumer string, noWait bool) error {
+		close(delivery)
+		return nil
+	}
+
+	release := make(chan struct{})
+	defer close(release)
+	cons, err := client.NewConsumer("foo", D(func(ctx context.Context, d *Delivery[[]byte]) Action {
+		if string(*d.Msg) == "stuck" {
+			// the handler ignores the context
+			<-release
+		}
+		return Ack
+	}), SetHandlerTimeout(10*time.Millisecond), SetTimeoutAction(Reject), SetPrefetchCount(1))
+	require.NoError(t, err)
+
+	// the semaphore is released, so the next delivery is handled
+	assert.Equal(t, uint64(2), <-acked)
+	require.Len(t, ack.RejectCalls(), 1)
+	assert.Equal(t, uint64(1), ack.RejectCalls()[0].Tag)
+
+	stats := cons.Stats()
+	assert.Equal(t, uint64(1), stats.TimedOut)
+	assert.Equal(t, uint64(1), stats.Rejected)
+}